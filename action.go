@@ -2,7 +2,9 @@ package carapace
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"reflect"
 	"regexp"
 	"runtime"
 	"strings"
@@ -11,6 +13,7 @@ import (
 	shlex "github.com/carapace-sh/carapace-shlex"
 	"github.com/carapace-sh/carapace/internal/cache"
 	"github.com/carapace-sh/carapace/internal/common"
+	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/carapace-sh/carapace/pkg/cache/key"
 	"github.com/carapace-sh/carapace/pkg/match"
 	"github.com/carapace-sh/carapace/pkg/style"
@@ -22,6 +25,7 @@ type Action struct {
 	meta      common.Meta
 	rawValues common.RawValues
 	callback  CompletionCallback
+	spec      []string // symbolic representation for spec export
 }
 
 // ActionMap maps Actions to an identifier.
@@ -46,7 +50,7 @@ func (a Action) Cache(timeout time.Duration, keys ...key.Key) Action {
 			}
 
 			invokedAction := (Action{callback: cachedCallback}).Invoke(c)
-			if invokedAction.action.meta.Messages.IsEmpty() {
+			if !invokedAction.action.meta.Messages.HasErrors() { // only skip caching on errors
 				if cacheFile, err := cache.File(file, line, keys...); err == nil { // regenerate as cache keys might have changed due to invocation
 					_ = cache.WriteE(cacheFile, invokedAction.export())
 				}
@@ -108,6 +112,21 @@ func (a Action) FilterParts() Action {
 	})
 }
 
+// InsertSuffix sets a suffix inserted after values instead of a space.
+// It is removed when one of given characters is typed next (only supported by zsh).
+//
+//	ActionValues("key").InsertSuffix("=", " =")
+func (a Action) InsertSuffix(suffix string, removeChars string) Action {
+	return ActionCallback(func(c Context) Action {
+		invoked := a.Invoke(c)
+		for index := range invoked.action.rawValues {
+			invoked.action.rawValues[index].InsertSuffix = suffix
+			invoked.action.rawValues[index].RemoveChars = removeChars
+		}
+		return invoked.ToA()
+	})
+}
+
 // Invoke executes the callback of an action if it exists (supports nesting).
 func (a Action) Invoke(c Context) InvokedAction {
 	if c.Args == nil {
@@ -121,13 +140,35 @@ func (a Action) Invoke(c Context) InvokedAction {
 	}
 
 	if a.rawValues == nil && a.callback != nil {
+		start := time.Now()
 		result := a.callback(c).Invoke(c)
 		result.action.meta.Merge(a.meta)
+		log.Logger.Debug("invoked callback", "callback", callbackName{a.callback}, "value", c.Value, "values", len(result.action.rawValues), "duration", time.Since(start))
 		return result
 	}
 	return InvokedAction{a}
 }
 
+// callbackName resolves the function name of a callback only when it is actually logged.
+type callbackName struct {
+	callback CompletionCallback
+}
+
+func (n callbackName) LogValue() slog.Value {
+	if f := runtime.FuncForPC(reflect.ValueOf(n.callback).Pointer()); f != nil {
+		return slog.StringValue(f.Name())
+	}
+	return slog.StringValue("unknown")
+}
+
+// KeepOrder keeps the order of the values instead of sorting them.
+func (a Action) KeepOrder() Action {
+	return ActionCallback(func(c Context) Action {
+		a.meta.KeepOrder = true
+		return a
+	}).withSpec(a.spec...)
+}
+
 // List wraps the Action in an ActionMultiParts with given divider.
 func (a Action) List(divider string) Action {
 	return ActionMultiParts(divider, func(c Context) Action {
@@ -225,6 +266,23 @@ func (a Action) NoSpace(suffixes ...rune) Action {
 		}
 		a.meta.Nospace.Add(suffixes...)
 		return a
+	}).withSpec(a.spec...)
+}
+
+// NoSpaceF disables space suffix for values where given function returns true.
+//
+//	ActionValues("origin/", "HEAD").NoSpaceF(func(s string) bool {
+//		return strings.HasSuffix(s, "/")
+//	})
+func (a Action) NoSpaceF(f func(s string) bool) Action {
+	return ActionCallback(func(c Context) Action {
+		invoked := a.Invoke(c)
+		for index, v := range invoked.action.rawValues {
+			if f(v.Value) {
+				invoked.action.rawValues[index].Nospace = true
+			}
+		}
+		return invoked.ToA()
 	})
 }
 
@@ -302,7 +360,7 @@ func (a Action) split(pipelines bool) Action {
 
 		if pipelines { // support redirects
 			if len(tokens) > 1 && tokens[len(tokens)-2].WordbreakType.IsRedirect() {
-				LOG.Printf("completing files for redirect arg %#v", tokens.Words().CurrentToken().Value)
+				log.Logger.Debug("completing files for redirect", "arg", tokens.Words().CurrentToken().Value)
 				prefix = originalValue[:tokens.CurrentToken().Index]
 				c.Value = tokens.CurrentToken().Value
 				a = ActionFiles()
@@ -311,7 +369,7 @@ func (a Action) split(pipelines bool) Action {
 
 		invoked := a.Invoke(c)
 		for index, value := range invoked.action.rawValues {
-			if !invoked.action.meta.Nospace.Matches(value.Value) || strings.Contains(value.Value, " ") { // TODO special characters
+			if !value.IsNospace(invoked.action.meta.Nospace) || strings.Contains(value.Value, " ") { // TODO special characters
 				switch tokens.CurrentToken().State {
 				case shlex.QUOTING_ESCAPING_STATE:
 					invoked.action.rawValues[index].Value = fmt.Sprintf(`"%v"`, strings.ReplaceAll(value.Value, `"`, `\"`))
@@ -321,7 +379,7 @@ func (a Action) split(pipelines bool) Action {
 					invoked.action.rawValues[index].Value = strings.Replace(value.Value, ` `, `\ `, -1)
 				}
 			}
-			if !invoked.action.meta.Nospace.Matches(value.Value) {
+			if !value.IsNospace(invoked.action.meta.Nospace) {
 				invoked.action.rawValues[index].Value += " "
 			}
 		}
@@ -350,7 +408,7 @@ func (a Action) StyleF(f func(s string, sc style.Context) string) Action {
 			invoked.action.rawValues[index].Style = f(v.Value, c)
 		}
 		return invoked.ToA()
-	})
+	}).withSpec(a.spec...)
 }
 
 // Style sets the style using a reference.
@@ -375,7 +433,7 @@ func (a Action) Suffix(suffix string) Action {
 	})
 }
 
-// Suppress suppresses specific error messages using regular expressions.
+// Suppress suppresses specific messages (any severity) using regular expressions.
 func (a Action) Suppress(expr ...string) Action {
 	return ActionCallback(func(c Context) Action {
 		invoked := a.Invoke(c)
@@ -386,6 +444,30 @@ func (a Action) Suppress(expr ...string) Action {
 	})
 }
 
+// SuppressInfo is like Suppress but only affects messages with severity info.
+//
+//	carapace.ActionInfo("example hint").SuppressInfo(".*")
+func (a Action) SuppressInfo(expr ...string) Action {
+	return a.suppressSeverity(common.SeverityInfo, expr...)
+}
+
+// SuppressWarning is like Suppress but only affects messages with severity warning.
+//
+//	carapace.ActionWarning("example warning").SuppressWarning("example")
+func (a Action) SuppressWarning(expr ...string) Action {
+	return a.suppressSeverity(common.SeverityWarning, expr...)
+}
+
+func (a Action) suppressSeverity(severity common.Severity, expr ...string) Action {
+	return ActionCallback(func(c Context) Action {
+		invoked := a.Invoke(c)
+		if err := invoked.action.meta.Messages.SuppressSeverity(severity, expr...); err != nil {
+			return ActionMessage(err.Error())
+		}
+		return invoked.ToA()
+	})
+}
+
 // Tag sets the tag.
 //
 //	ActionValues("192.168.1.1", "127.0.0.1").Tag("interfaces").
@@ -407,7 +489,7 @@ func (a Action) TagF(f func(s string) string) Action {
 			invoked.action.rawValues[index].Tag = f(v.Value)
 		}
 		return invoked.ToA()
-	})
+	}).withSpec(a.spec...)
 }
 
 // Timeout sets the maximum duration an Action may take to invoke.
@@ -476,5 +558,11 @@ func (a Action) UsageF(f func() string) Action {
 			a.meta.Usage = usage
 		}
 		return a
-	})
+	}).withSpec(a.spec...)
+}
+
+// withSpec sets the symbolic representation used for spec export.
+func (a Action) withSpec(spec ...string) Action {
+	a.spec = spec
+	return a
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -113,6 +114,29 @@ func TestNoSpace(t *testing.T) {
 	}
 }
 
+func TestNoSpaceF(t *testing.T) {
+	a := ActionValues("origin/", "HEAD").NoSpaceF(func(s string) bool {
+		return strings.HasSuffix(s, "/")
+	}).Invoke(Context{})
+
+	for _, val := range a.action.rawValues {
+		if nospace := val.IsNospace(a.action.meta.Nospace); nospace != (val.Value == "origin/") {
+			t.Errorf("unexpected nospace for %#v: %v", val.Value, nospace)
+		}
+	}
+}
+
+func TestInsertSuffix(t *testing.T) {
+	a := ActionValues("key").InsertSuffix("=", " =").Invoke(Context{})
+	if val := a.action.rawValues[0]; val.InsertSuffix != "=" || val.RemoveChars != " =" {
+		t.Errorf("unexpected value: %#v", val)
+	}
+
+	if actual := cobraValuesFor(a); actual[0] != "key=" {
+		t.Errorf("insert suffix should be appended for cobra: %#v", actual)
+	}
+}
+
 func TestActionDirectories(t *testing.T) {
 	assertEqual(t,
 		ActionStyledValues(
@@ -226,6 +250,50 @@ func TestActionMessageSuppress(t *testing.T) {
 	)
 }
 
+func TestActionMessageSeverity(t *testing.T) {
+	expected := ActionValues()
+	expected.meta.Messages.AddSeverity("example warning", common.SeverityWarning)
+	expected.meta.Messages.AddSeverity("example info", common.SeverityInfo)
+
+	assertEqual(t,
+		expected.Invoke(Context{}),
+		Batch(
+			ActionWarning("example warning"),
+			ActionInfo("example info"),
+		).ToA().Invoke(Context{}),
+	)
+}
+
+func TestActionMessageSuppressSeverity(t *testing.T) {
+	expected := ActionValues("test")
+	expected.meta.Messages.Add("example")
+
+	assertEqual(t,
+		expected.Invoke(Context{}),
+		Batch(
+			ActionMessage("example").SuppressWarning("example"),
+			ActionWarning("example warning").SuppressWarning("example"),
+			ActionInfo("example info").SuppressInfo(".*"),
+			ActionValues("test"),
+		).ToA().Invoke(Context{}),
+	)
+}
+
+func TestCacheSeverity(t *testing.T) {
+	f := func() Action {
+		return ActionCallback(func(c Context) Action {
+			return Batch(
+				ActionValues(time.Now().String()),
+				ActionInfo("cached despite info"),
+			).ToA()
+		}).Cache(15 * time.Millisecond)
+	}
+
+	a1 := f().Invoke(Context{})
+	a2 := f().Invoke(Context{})
+	assertEqual(t, a1, a2)
+}
+
 func TestActionExecCommand(t *testing.T) {
 	context := NewContext()
 	context.Value = "docs/"
@@ -239,3 +307,9 @@ func TestActionExecCommand(t *testing.T) {
 		ActionExecCommand("head", "-n1", "go.mod")(func(output []byte) Action { return ActionValues(string(output)) }).Invoke(Context{}),
 	)
 }
+
+func TestCallbackName(t *testing.T) {
+	if name := (callbackName{ActionValues().callback}).LogValue().String(); name != "github.com/carapace-sh/carapace.ActionValues.func1" {
+		t.Errorf("unexpected callback name: %v", name)
+	}
+}
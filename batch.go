@@ -34,9 +34,18 @@ func (b batch) Invoke(c Context) invokedBatch {
 //		return batch.Invoke(c).Merge().ToA()
 //	})
 func (b batch) ToA() Action {
+	spec := make([]string, 0)
+	for _, a := range b {
+		if a.spec == nil {
+			spec = nil
+			break
+		}
+		spec = append(spec, a.spec...)
+	}
+
 	return ActionCallback(func(c Context) Action {
 		return b.Invoke(c).Merge().ToA()
-	})
+	}).withSpec(spec...)
 }
 
 // Merge merges Actions of a batch.
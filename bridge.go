@@ -0,0 +1,435 @@
+package carapace
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/cache"
+	"github.com/carapace-sh/carapace/internal/env"
+	"github.com/carapace-sh/carapace/pkg/execlog"
+	"github.com/carapace-sh/carapace/pkg/style"
+	capture "github.com/carapace-sh/carapace/third_party/github.com/Valodim/zsh-capture-completion"
+)
+
+// bashWordbreaks are the characters of COMP_WORDBREAKS relevant for already split arguments.
+const bashWordbreaks = "=:"
+
+const bridgeBashScript = `
+command="$1"
+count="$2"
+shift 2
+scripts=("${@:1:count}")
+shift "${count}"
+
+COMP_LINE="$1" # unsplit line as COMP_WORDS is split on COMP_WORDBREAKS
+shift
+COMP_WORDS=("$@")
+COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+COMP_POINT=${#COMP_LINE}
+COMP_TYPE=9
+COMP_KEY=9
+COMPREPLY=()
+
+if [ "${#scripts[@]}" -eq 0 ]; then
+  for loader in \
+    /usr/share/bash-completion/bash_completion \
+    /usr/local/share/bash-completion/bash_completion \
+    /opt/homebrew/share/bash-completion/bash_completion \
+    /etc/bash_completion; do
+    if [ -f "${loader}" ]; then
+      . "${loader}"
+      break
+    fi
+  done
+
+  if declare -F _comp_load >/dev/null; then
+    _comp_load -- "${command}"
+  elif declare -F __load_completion >/dev/null; then
+    __load_completion "${command}"
+  fi
+else
+  for script in "${scripts[@]}"; do
+    . "${script}"
+  done
+fi >/dev/null 2>&1
+
+spec="$(complete -p "${command}" 2>/dev/null)" || {
+  echo "no completion registered for ${command}" >&2
+  exit 1
+}
+
+options=" "
+read -r -a parts <<<"${spec}"
+for ((i = 0; i < ${#parts[@]}; i++)); do
+  [ "${parts[i]}" = "-o" ] && options="${options}${parts[i+1]} "
+done
+
+# compopt only works during interactive completion so track the options here
+compopt() {
+  while [ $# -gt 0 ]; do
+    case "$1" in
+    -o) options="${options}$2 "; shift ;;
+    +o) options="${options// $2 / }"; shift ;;
+    esac
+    shift
+  done
+}
+
+cur="${COMP_WORDS[COMP_CWORD]}"
+prev="${COMP_WORDS[COMP_CWORD-1]}"
+if [[ "${spec}" =~ -F\ ([^ ]+) ]]; then
+  "${BASH_REMATCH[1]}" "${command}" "${cur}" "${prev}" >/dev/null 2>&1
+else
+  spec="${spec#complete }"
+  mapfile -t COMPREPLY < <(eval "compgen ${spec% *} -- \"\${cur}\"" 2>/dev/null)
+fi
+
+echo "${options}"
+for reply in "${COMPREPLY[@]}"; do
+  if [[ "${options}" == *" filenames "* ]] && [ -d "${reply}" ]; then
+    reply="${reply%/}/"
+  fi
+  echo "${reply}"
+done
+`
+
+// ActionBridgeBash bridges completions registered with `complete -F` in bash.
+// Given scripts are sourced, otherwise the bash-completion loader is used.
+//
+//	carapace.ActionBridgeBash("git")
+//	carapace.ActionBridgeBash("tool", "/path/to/tool.bash")
+func ActionBridgeBash(command string, scripts ...string) Action {
+	return ActionCallback(func(c Context) Action {
+		words, prefix := bashWords(c.Value)
+
+		args := []string{"--norc", "--noprofile", "-c", bridgeBashScript, "bash", command, strconv.Itoa(len(scripts))}
+		args = append(args, scripts...)
+		args = append(args, strings.Join(append(append([]string{command}, c.Args...), c.Value), " "))
+		args = append(args, command)
+		for _, arg := range c.Args {
+			argWords, _ := bashWords(arg)
+			if len(argWords) > 1 && argWords[len(argWords)-1] == "" {
+				argWords = argWords[:len(argWords)-1] // `--opt=` is followed by a separate word
+			}
+			args = append(args, argWords...)
+		}
+		args = append(args, words...)
+
+		return ActionExecCommand("bash", args...)(func(output []byte) Action {
+			lines := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+			options := strings.Fields(lines[0])
+			values := lines[1:]
+			if len(values) == 1 && values[0] == "" {
+				values = values[:0]
+			}
+
+			var a Action
+			switch {
+			case len(values) == 0 && (containsString(options, "default") || containsString(options, "bashdefault")):
+				return ActionFiles().Prefix(prefix)
+			case containsString(options, "filenames"):
+				a = ActionValues(values...).StyleF(style.ForPath).NoSpace('/')
+			default:
+				a = ActionValues(values...)
+			}
+
+			if containsString(options, "nospace") {
+				a = a.NoSpace()
+			}
+			return a.Prefix(prefix)
+		})
+	})
+}
+
+// zshCaptureScript patches the vendored capture script:
+//   - the compinit dump is configurable with CARAPACE_ZSH_COMPDUMP
+//   - the example `source <(example _carapace zsh)` is removed
+//   - each value is prefixed with its tag (`$curtag<TAB>`)
+var zshCaptureScript = strings.NewReplacer(
+	"compinit -d ~/.zcompdump_capture\n", "compinit -d \"${CARAPACE_ZSH_COMPDUMP:-$HOME/.zcompdump_capture}\"\n",
+	"source <(example _carapace zsh)\n", "",
+	"echo -E - $IPREFIX", "echo -E - $curtag$''\\t''$IPREFIX",
+).Replace(capture.Script)
+
+// ActionBridgeZsh bridges completions of zsh completion functions (`_command`).
+// The completion is captured from a `zsh -f` pseudo-terminal (requires the zsh/zpty module).
+//
+//	carapace.ActionBridgeZsh("git")
+func ActionBridgeZsh(command string) Action {
+	return ActionCallback(func(c Context) Action {
+		if dir, err := cache.CacheDir("bridge"); err == nil {
+			c.Setenv(env.CARAPACE_ZSH_COMPDUMP, filepath.Join(dir, "zcompdump")) // reuse compinit dump between invocations
+		}
+
+		words := []string{zshQuoter.Replace(command)}
+		for _, arg := range c.Args {
+			words = append(words, zshQuoter.Replace(arg))
+		}
+		words = append(words, zshQuoter.Replace(c.Value))
+
+		return ActionExecCommand("zsh", "-f", "-c", zshCaptureScript, "capture", strings.Join(words, " "))(func(output []byte) Action {
+			type candidate struct{ value, description string }
+
+			tags := make(map[string][]candidate)
+			unique := make(map[string]bool)
+			for _, line := range strings.Split(string(output), "\n") {
+				line = strings.TrimRight(line, "\r")
+				tag, value := "", line
+				if splitted := strings.SplitN(line, "\t", 2); len(splitted) == 2 {
+					tag, value = splitted[0], splitted[1]
+				}
+
+				description := ""
+				if splitted := strings.SplitN(value, " -- ", 2); len(splitted) == 2 {
+					value = splitted[0]
+					description = strings.TrimPrefix(strings.TrimSpace(splitted[1]), "-- ") // `_describe` descriptions contain the separator as well
+				}
+
+				if value == "" || unique[value] {
+					continue
+				}
+				unique[value] = true
+				tags[tag] = append(tags[tag], candidate{value, description})
+			}
+
+			names := make([]string, 0, len(tags))
+			for tag := range tags {
+				names = append(names, tag)
+			}
+			sort.Strings(names)
+
+			batch := Batch()
+			for _, tag := range names {
+				vals := make([]string, 0, len(tags[tag])*2)
+				for _, candidate := range tags[tag] {
+					vals = append(vals, candidate.value, candidate.description)
+				}
+				batch = append(batch, ActionValuesDescribed(vals...).Tag(tag))
+			}
+			return batch.ToA().NoSpace('/', '=')
+		}).Invoke(c).ToA()
+	})
+}
+
+// ActionBridgeCobra bridges completions of cobra based commands using the hidden `__complete` command.
+// Additional arguments are passed before the ones from the Context.
+//
+//	carapace.ActionBridgeCobra("kubectl")
+//	carapace.ActionBridgeCobra("gh", "repo")
+func ActionBridgeCobra(command string, arg ...string) Action {
+	return ActionCallback(func(c Context) Action {
+		args := []string{"__complete"}
+		args = append(args, arg...)
+		args = append(args, c.Args...)
+		args = append(args, c.Value)
+		return ActionExecCommand(command, args...)(func(output []byte) Action {
+			return ActionImportCobra(output)
+		})
+	})
+}
+
+// ActionBridgeFish bridges completions of fish using `complete --do-complete`.
+//
+//	carapace.ActionBridgeFish("git")
+func ActionBridgeFish(command string) Action {
+	return ActionCallback(func(c Context) Action {
+		if _, err := execlog.LookPath("fish"); err != nil {
+			if errors.Is(err, exec.ErrNotFound) {
+				return ActionMessage("fish not found in PATH")
+			}
+			return ActionMessage(err.Error())
+		}
+
+		words := []string{fishQuoter.Replace(command)}
+		for _, arg := range c.Args {
+			words = append(words, fishQuoter.Replace(arg))
+		}
+		words = append(words, fishQuoter.Replace(c.Value))
+
+		return ActionExecCommand("fish", "--no-config", "-c", `complete --do-complete="$argv[1]"`, strings.Join(words, " "))(func(output []byte) Action {
+			vals := make([]string, 0)
+			for _, line := range strings.Split(string(output), "\n") {
+				if line == "" {
+					continue
+				}
+				splitted := strings.SplitN(line, "\t", 2)
+				if len(splitted) == 1 {
+					splitted = append(splitted, "")
+				}
+				vals = append(vals, splitted[0], splitted[1])
+			}
+
+			return ActionValuesDescribed(vals...).StyleF(func(s string, sc style.Context) string {
+				if strings.HasSuffix(s, "/") {
+					return style.ForPath(s, sc)
+				}
+				if abs, err := c.Abs(s); err == nil {
+					if _, err := os.Stat(abs); err == nil {
+						return style.ForPath(s, sc)
+					}
+				}
+				return ""
+			}).NoSpace('/')
+		})
+	})
+}
+
+// ActionBridgeArgcomplete bridges completions of python programs using argcomplete.
+// Candidates are written to file descriptor 8 and separated by `_ARGCOMPLETE_IFS`.
+//
+//	carapace.ActionBridgeArgcomplete("az")
+func ActionBridgeArgcomplete(command string) Action {
+	return ActionCallback(func(c Context) Action {
+		words := []string{zshQuoter.Replace(command)} // backslash escaping is understood by shlex as well
+		for _, arg := range c.Args {
+			words = append(words, zshQuoter.Replace(arg))
+		}
+		words = append(words, zshQuoter.Replace(c.Value))
+		line := strings.Join(words, " ")
+
+		c.Setenv("_ARGCOMPLETE", "1")
+		c.Setenv("_ARGCOMPLETE_SHELL", "fish")
+		c.Setenv("_ARGCOMPLETE_IFS", "\n")
+		c.Setenv("_ARGCOMPLETE_DFS", "\t")
+		c.Setenv("_ARGCOMPLETE_SUPPRESS_SPACE", "1")
+		c.Setenv("COMP_LINE", line)
+		c.Setenv("COMP_POINT", strconv.Itoa(len(line)))
+		c.Setenv("COMP_TYPE", "9")
+
+		return ActionExecCommand("sh", "-c", `"$@" 8>&1 9>/dev/null 1>/dev/null`, "argcomplete", command)(func(output []byte) Action {
+			vals := make([]string, 0)
+			for _, line := range strings.Split(string(output), "\n") {
+				if line == "" {
+					continue
+				}
+				splitted := strings.SplitN(line, "\t", 2)
+				if len(splitted) == 1 {
+					splitted = append(splitted, "")
+				}
+				vals = append(vals, strings.TrimSuffix(splitted[0], " "), splitted[1])
+			}
+			return ActionValuesDescribed(vals...).NoSpace('/', '=')
+		}).Invoke(c).ToA()
+	})
+}
+
+// ActionBridgeClick bridges completions of python programs using click.
+// The completion is requested with `_<PROG>_COMPLETE=zsh_complete`.
+//
+//	carapace.ActionBridgeClick("flask")
+func ActionBridgeClick(command string) Action {
+	return ActionCallback(func(c Context) Action {
+		words := []string{zshQuoter.Replace(command)} // backslash escaping is understood by shlex as well
+		for _, arg := range c.Args {
+			words = append(words, zshQuoter.Replace(arg))
+		}
+		words = append(words, zshQuoter.Replace(c.Value))
+
+		prog := strings.TrimSuffix(filepath.Base(command), ".py")
+		c.Setenv(strings.ToUpper(strings.Replace("_"+prog+"_COMPLETE", "-", "_", -1)), "zsh_complete")
+		c.Setenv("COMP_WORDS", strings.Join(words, " "))
+		c.Setenv("COMP_CWORD", strconv.Itoa(len(c.Args)+1))
+
+		return ActionExecCommand(command)(func(output []byte) Action {
+			lines := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+
+			batch := Batch()
+			vals := make([]string, 0)
+			for index := 0; index+2 < len(lines); index += 3 {
+				switch lines[index] {
+				case "dir":
+					batch = append(batch, ActionDirectories())
+				case "file":
+					batch = append(batch, ActionFiles())
+				default:
+					value, description := lines[index+1], lines[index+2]
+					if description == "_" {
+						description = ""
+					} else {
+						value = strings.Replace(value, `\:`, ":", -1)
+					}
+					vals = append(vals, value, description)
+				}
+			}
+			return append(batch, ActionValuesDescribed(vals...)).ToA()
+		}).Invoke(c).ToA()
+	})
+}
+
+var fishQuoter = strings.NewReplacer(
+	`\`, `\\`,
+	` `, `\ `,
+	`'`, `\'`,
+	`"`, `\"`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`|`, `\|`,
+	`;`, `\;`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`(`, `\(`,
+	`)`, `\)`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`*`, `\*`,
+	`?`, `\?`,
+	`#`, `\#`,
+)
+
+var zshQuoter = strings.NewReplacer(
+	`\`, `\\`,
+	` `, `\ `,
+	`'`, `\'`,
+	`"`, `\"`,
+	"`", "\\`",
+	`$`, `\$`,
+	`&`, `\&`,
+	`|`, `\|`,
+	`;`, `\;`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`(`, `\(`,
+	`)`, `\)`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`*`, `\*`,
+	`?`, `\?`,
+	`#`, `\#`,
+	`!`, `\!`,
+)
+
+// bashWords splits given value like bash does using COMP_WORDBREAKS.
+// It returns the words along with the prefix preceding the last word.
+func bashWords(value string) (words []string, prefix string) {
+	words = make([]string, 0)
+	start := 0
+	for index, r := range value {
+		if strings.ContainsRune(bashWordbreaks, r) {
+			if index > start {
+				words = append(words, value[start:index])
+			}
+			words = append(words, string(r))
+			start = index + 1
+			prefix = value[:start]
+		}
+	}
+	return append(words, value[start:]), prefix
+}
+
+func containsString(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
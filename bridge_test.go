@@ -0,0 +1,20 @@
+package carapace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestZshCaptureScript(t *testing.T) {
+	for _, s := range []string{
+		`compinit -d "${CARAPACE_ZSH_COMPDUMP:-$HOME/.zcompdump_capture}"`,
+		`echo -E - $curtag$''\t''$IPREFIX`,
+	} {
+		if !strings.Contains(zshCaptureScript, s) {
+			t.Errorf("patch not applied to capture script: %v", s)
+		}
+	}
+	if strings.Contains(zshCaptureScript, "source <(example") {
+		t.Error("patch not applied to capture script: source <(example _carapace zsh)")
+	}
+}
@@ -2,9 +2,11 @@
 package carapace
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/carapace-sh/carapace/internal/shell"
+	"github.com/carapace-sh/carapace/internal/spec"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -85,6 +87,17 @@ func (c Carapace) FlagCompletion(actions ActionMap) {
 	}
 }
 
+// Args sets the arity exported in specs (a max of -1 means unbounded).
+// It does not validate arguments and should match `cobra.Command.Args`.
+//
+//	carapace.Gen(cmd).Args(1, -1)
+func (c Carapace) Args(min, max int) {
+	if c.cmd.Annotations == nil {
+		c.cmd.Annotations = make(map[string]string)
+	}
+	c.cmd.Annotations[spec.AnnotationArgs] = fmt.Sprintf("%v %v", min, max)
+}
+
 const annotation_standalone = "carapace_standalone"
 
 // Standalone prevents cobra defaults interfering with standalone mode (e.g. implicit help command).
@@ -130,4 +143,9 @@ func Test(t interface{ Error(args ...interface{}) }) {
 	for _, e := range storage.check() {
 		t.Error(e)
 	}
+	if isConfigSandboxed() { // don't depend on the overlays of the user running the tests
+		for _, e := range checkOverlays() {
+			t.Error(e)
+		}
+	}
 }
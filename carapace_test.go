@@ -198,6 +198,14 @@ func TestSnippet(t *testing.T) {
 		t.Error("fish failed")
 	}
 
+	if s, _ := Gen(cmd).Snippet("ksh"); !strings.Contains(s, ".sh.edchar") {
+		t.Error("ksh failed")
+	}
+
+	if s, _ := Gen(cmd).Snippet("murex"); !strings.Contains(s, "autocomplete set") {
+		t.Error("murex failed")
+	}
+
 	if s, _ := Gen(cmd).Snippet("oil"); !strings.Contains(s, "#!/bin/osh") {
 		t.Error("oil failed")
 	}
@@ -210,6 +218,10 @@ func TestSnippet(t *testing.T) {
 		t.Error("xonsh failed")
 	}
 
+	if s, _ := Gen(cmd).Snippet("yash"); !strings.Contains(s, "completion/") {
+		t.Error("yash failed")
+	}
+
 	if s, _ := Gen(cmd).Snippet("zsh"); !strings.Contains(s, "compdef") {
 		t.Error("zsh")
 	}
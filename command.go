@@ -1,16 +1,30 @@
 package carapace
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/carapace-sh/carapace/internal/config"
+	"github.com/carapace-sh/carapace/internal/env"
+	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/carapace-sh/carapace/internal/spec"
+	"github.com/carapace-sh/carapace/pkg/settings"
 	"github.com/carapace-sh/carapace/pkg/style"
 	"github.com/spf13/cobra"
 )
 
+// loadSettings loads the config file and reconfigures logging accordingly.
+func loadSettings() error {
+	err := config.LoadSettings()
+	log.Configure()
+	return err
+}
+
 func addCompletionCommand(targetCmd *cobra.Command) {
 	for _, c := range targetCmd.Commands() {
 		if c.Name() == "_carapace" {
@@ -22,8 +36,7 @@ func addCompletionCommand(targetCmd *cobra.Command) {
 		Use:    "_carapace",
 		Hidden: true,
 		Run: func(cmd *cobra.Command, args []string) {
-			LOG.Print(strings.Repeat("-", 80))
-			LOG.Printf("%#v", os.Args)
+			log.Logger.Debug("invoked", "args", os.Args)
 
 			if len(args) > 2 && strings.HasPrefix(args[2], "_") {
 				cmd.Hidden = false
@@ -40,9 +53,11 @@ func addCompletionCommand(targetCmd *cobra.Command) {
 			}
 
 			if s, err := complete(parentCmd, args); err != nil {
-				fmt.Fprintln(io.MultiWriter(parentCmd.OutOrStderr(), LOG.Writer()), err.Error())
+				log.Logger.Error("completion failed", "error", err.Error())
+				fmt.Fprintln(parentCmd.OutOrStderr(), err.Error())
 			} else {
-				fmt.Fprintln(io.MultiWriter(parentCmd.OutOrStdout(), LOG.Writer()), s)
+				log.Logger.Debug("completion output", "output", s)
+				fmt.Fprintln(parentCmd.OutOrStdout(), s)
 			}
 		},
 		FParseErrWhitelist: cobra.FParseErrWhitelist{
@@ -61,11 +76,14 @@ func addCompletionCommand(targetCmd *cobra.Command) {
 			"export", style.Default,
 			"fish", "#7ea8fc",
 			"ion", "#0e5d6d",
+			"ksh", "#6c8cd5",
+			"murex", "#d0562a",
 			"nushell", "#29d866",
 			"oil", "#373a36",
 			"powershell", "#e8a16f",
 			"tcsh", "#412f09",
 			"xonsh", "#a8ffa9",
+			"yash", "#b5651d",
 			"zsh", "#efda53",
 		),
 		ActionValues(targetCmd.Root().Name()),
@@ -92,10 +110,187 @@ func addCompletionCommand(targetCmd *cobra.Command) {
 	specCmd := &cobra.Command{
 		Use: "spec",
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Fprint(cmd.OutOrStdout(), spec.Spec(targetCmd))
+			format, _ := cmd.Flags().GetString("format")
+			s, err := spec.Format(spec.New(targetCmd, specCompletion), format)
+			if err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+			fmt.Fprint(cmd.OutOrStdout(), s)
 		},
 	}
+	specCmd.Flags().String("format", "yaml", "output format [json|markdown|yaml]")
 	carapaceCmd.AddCommand(specCmd)
+	Carapace{specCmd}.FlagCompletion(ActionMap{
+		"format": ActionValues("json", "markdown", "yaml"),
+	})
+
+	specSchemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "print the json schema of the spec",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(cmd.OutOrStdout(), spec.Schema())
+		},
+	}
+	specCmd.AddCommand(specSchemaCmd)
+
+	specDiffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "compare with given spec and exit with 1 on breaking changes",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			content, err := os.ReadFile(args[0])
+			if err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+
+			current := spec.Spec(targetCmd, specCompletion)
+			changes, err := spec.CompareYaml(content, []byte(current))
+			if err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), spec.Unified(args[0], "current", string(content), current))
+			for _, change := range changes {
+				fmt.Fprintln(cmd.OutOrStdout(), change.String())
+			}
+
+			if spec.HasBreaking(changes) {
+				os.Exit(1)
+			}
+		},
+	}
+	specCmd.AddCommand(specDiffCmd)
+	Carapace{specDiffCmd}.PositionalCompletion(
+		ActionFiles(".yaml", ".yml"),
+	)
+
+	macroCmd := &cobra.Command{
+		Use:                "macro [name] [args] -- [words]",
+		Short:              "invoke a macro or list available ones",
+		DisableFlagParsing: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				macros.RLock()
+				names := make([]string, 0, len(macros.entries))
+				for name := range macros.entries {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					fmt.Fprintf(cmd.OutOrStdout(), "%v\t%v\n", name, macros.entries[name].description)
+				}
+				macros.RUnlock()
+				return
+			}
+
+			m, ok := lookupMacro(args[0])
+			if !ok {
+				fmt.Fprintf(cmd.ErrOrStderr(), "unknown macro: %v\n", args[0])
+				os.Exit(1)
+			}
+
+			macroArgs, words := splitDash(args[1:])
+			context := NewContext(words...)
+			fmt.Fprintln(cmd.OutOrStdout(), m.action(macroArgs...).Invoke(context).value("export", context.Value))
+		},
+	}
+	carapaceCmd.AddCommand(macroCmd)
+	Carapace{macroCmd}.PositionalCompletion(
+		actionMacros(),
+	)
+	Carapace{macroCmd}.PositionalAnyCompletion(
+		ActionCallback(func(c Context) Action {
+			m, ok := lookupMacro(c.Args[0])
+			if !ok {
+				return ActionMessage("unknown macro: %v", c.Args[0])
+			}
+
+			macroArgs, words := splitDash(c.Args[1:])
+			if len(macroArgs) == len(c.Args)-1 {
+				return ActionValues("--")
+			}
+			c.Args = words
+			return m.action(macroArgs...).Invoke(c).ToA()
+		}),
+	)
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "manage settings",
+		Args:  cobra.NoArgs,
+		Run:   func(cmd *cobra.Command, args []string) {},
+	}
+	carapaceCmd.AddCommand(configCmd)
+
+	configGetCmd := &cobra.Command{
+		Use:   "get key",
+		Short: "print the value of a setting",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := loadSettings(); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+			}
+
+			value, err := settings.Get(args[0])
+			if err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+		},
+	}
+	configCmd.AddCommand(configGetCmd)
+	Carapace{configGetCmd}.PositionalCompletion(
+		actionSettings(),
+	)
+
+	configSetCmd := &cobra.Command{
+		Use:   "set key [value]",
+		Short: "set a setting (restores the default if value is omitted)",
+		Args:  cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			value := ""
+			if len(args) > 1 {
+				value = args[1]
+			}
+			if err := settings.Set(args[0], value); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	configCmd.AddCommand(configSetCmd)
+	Carapace{configSetCmd}.PositionalCompletion(
+		actionSettings(),
+		ActionCallback(func(c Context) Action {
+			return actionSettingValue(c.Args[0])
+		}),
+	)
+
+	configListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "list settings",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := loadSettings(); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			for _, name := range config.GetSettingConfigs() {
+				fields, _ := config.GetSettingFields(name)
+				for _, field := range fields {
+					fmt.Fprintf(w, "%v.%v\t%v\t%v\n", name, field.Name, field.Value, field.Description)
+				}
+			}
+			w.Flush()
+		},
+	}
+	configCmd.AddCommand(configListCmd)
 
 	styleCmd := &cobra.Command{
 		Use:  "style",
@@ -111,10 +306,10 @@ func addCompletionCommand(targetCmd *cobra.Command) {
 			for _, arg := range args {
 				if splitted := strings.SplitN(arg, "=", 2); len(splitted) == 2 {
 					if err := style.Set(splitted[0], splitted[1]); err != nil {
-						fmt.Fprint(cmd.ErrOrStderr(), err.Error())
+						fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
 					}
 				} else {
-					fmt.Fprintf(cmd.ErrOrStderr(), "invalid format: '%v'", arg)
+					fmt.Fprintf(cmd.ErrOrStderr(), "invalid format: '%v'\n", arg)
 				}
 			}
 		},
@@ -123,4 +318,154 @@ func addCompletionCommand(targetCmd *cobra.Command) {
 	Carapace{styleSetCmd}.PositionalAnyCompletion(
 		ActionStyleConfig(),
 	)
+
+	styleGetCmd := &cobra.Command{
+		Use:   "get key",
+		Short: "print the effective value of a style",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := loadSettings(); err != nil { // theme
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+			}
+			if err := config.Load(); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+			}
+
+			value, err := style.Get(args[0])
+			if err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+		},
+	}
+	styleCmd.AddCommand(styleGetCmd)
+	Carapace{styleGetCmd}.PositionalCompletion(
+		actionStyleKeys(""),
+	)
+
+	styleListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "list styles with a preview",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := config.Load(); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			for _, name := range config.GetStyleConfigs() {
+				fields, err := config.GetStyleFields(name)
+				if err != nil {
+					fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+					continue
+				}
+				for _, field := range fields {
+					preview := field.Style
+					if preview == "" {
+						preview = "default"
+					}
+					if !env.ColorDisabled() {
+						preview = fmt.Sprintf("\x1b[%vm%v\x1b[0m", style.SGR(field.Style), preview)
+					}
+					fmt.Fprintf(w, "%v.%v\t%v\t%v\n", name, field.Name, field.Description, preview)
+				}
+			}
+			w.Flush()
+		},
+	}
+	styleCmd.AddCommand(styleListCmd)
+
+	styleResetCmd := &cobra.Command{
+		Use:   "reset [key]",
+		Short: "reset a style, style configuration or all styles to default",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			key := ""
+			if len(args) > 0 {
+				key = args[0]
+			}
+			if err := style.Reset(key); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	styleCmd.AddCommand(styleResetCmd)
+	Carapace{styleResetCmd}.PositionalCompletion(
+		actionStyleKeys(""),
+	)
+
+	styleExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "export effective styles as theme",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := loadSettings(); err != nil { // theme
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+			}
+
+			content, err := style.Export()
+			if err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(content))
+		},
+	}
+	styleCmd.AddCommand(styleExportCmd)
+
+	styleImportCmd := &cobra.Command{
+		Use:   "import file",
+		Short: "import a theme (use - for stdin)",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flag("ls-colors").Changed {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			var content []byte
+			var err error
+			switch {
+			case cmd.Flag("ls-colors").Changed:
+				definition := style.LsColors(NewContext())
+				if len(args) > 0 {
+					definition = args[0]
+				}
+				content, err = json.Marshal(style.FromLsColors(definition))
+			case args[0] == "-":
+				content, err = io.ReadAll(cmd.InOrStdin())
+			default:
+				content, err = os.ReadFile(args[0])
+			}
+			if err == nil {
+				err = style.Import(content)
+			}
+			if err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	styleImportCmd.Flags().Bool("ls-colors", false, "import from LS_COLORS/EZA_COLORS or given definition")
+	styleCmd.AddCommand(styleImportCmd)
+	Carapace{styleImportCmd}.PositionalCompletion(
+		ActionCallback(func(c Context) Action {
+			if styleImportCmd.Flag("ls-colors").Changed {
+				return ActionValues()
+			}
+			return ActionFiles(".json")
+		}),
+	)
+}
+
+// splitDash splits given args at the first `--`.
+func splitDash(args []string) (before []string, after []string) {
+	for index, arg := range args {
+		if arg == "--" {
+			return args[:index], args[index+1:]
+		}
+	}
+	return args, []string{}
 }
@@ -0,0 +1,203 @@
+package carapace
+
+import (
+	"debug/buildinfo"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/carapace-sh/carapace/pkg/execlog"
+	"github.com/spf13/cobra"
+)
+
+var commandLine = struct {
+	sync.RWMutex
+	commands map[string]*cobra.Command
+	bridges  map[string]Action
+}{
+	commands: make(map[string]*cobra.Command),
+	bridges:  make(map[string]Action),
+}
+
+// RegisterCommand registers an in-process command tree used by ActionCommandLine.
+//
+//	carapace.RegisterCommand(toolCmd)
+func RegisterCommand(cmd *cobra.Command) {
+	commandLine.Lock()
+	defer commandLine.Unlock()
+	commandLine.commands[cmd.Name()] = cmd
+}
+
+// RegisterBridge registers an Action used by ActionCommandLine for given command.
+// It is invoked with `Context.Args` containing the arguments of the embedded command.
+//
+//	carapace.RegisterBridge("git", carapace.ActionBridgeZsh("git"))
+func RegisterBridge(command string, action Action) {
+	commandLine.Lock()
+	defer commandLine.Unlock()
+	commandLine.bridges[command] = action
+}
+
+var envAssignment = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*=`)
+
+// commandLineWrappers contains commands which run the command following their options
+// along with the options that consume an argument.
+var commandLineWrappers = map[string][]string{
+	"doas":  {"-C", "-u"},
+	"env":   {"-C", "--chdir", "-S", "--split-string", "-u", "--unset"},
+	"nice":  {"-n", "--adjustment"},
+	"nohup": {},
+	"sudo":  {"-C", "--close-from", "-D", "--chdir", "-g", "--group", "-h", "--host", "-p", "--prompt", "-R", "--chroot", "-r", "--role", "-T", "--command-timeout", "-t", "--type", "-U", "--other-user", "-u", "--user"},
+}
+
+// ActionCommandLine completes an embedded command line given as `Context.Args`.
+// Leading environment assignments (`FOO=1`), a `--` separator and wrappers like
+// `sudo -u root` or `env -i` are skipped to determine the embedded command.
+// Environment assignments are passed to the embedded command.
+// The completer is chosen in order of:
+//   - command registered with RegisterCommand
+//   - executable built with carapace (`_carapace export`)
+//   - bridge registered with RegisterBridge
+//   - files
+//
+// Use `Action.Shift` to skip arguments preceding the embedded command.
+//
+//	carapace.Gen(sudoCmd).PositionalAnyCompletion(
+//		carapace.ActionCommandLine(),
+//	)
+func ActionCommandLine() Action {
+	return ActionCallback(func(c Context) Action {
+		args, complete := commandLineBoundary(&c, c.Args)
+		switch {
+		case !complete:
+			return ActionValues() // argument of a wrapper option
+		case len(args) == 0:
+			if strings.ContainsRune(c.Value, '/') {
+				return ActionFiles()
+			}
+			return ActionExecutables()
+		}
+
+		name := filepath.Base(args[0])
+		c.Args = args[1:]
+
+		commandLine.RLock()
+		cmd, registered := commandLine.commands[name]
+		bridge, bridged := commandLine.bridges[name]
+		commandLine.RUnlock()
+
+		if registered {
+			return ActionExecute(cmd).Invoke(c).ToA()
+		}
+		if path, ok := carapaceExecutable(c, args[0]); ok {
+			exportArgs := []string{"_carapace", "export", ""}
+			exportArgs = append(exportArgs, c.Args...)
+			exportArgs = append(exportArgs, c.Value)
+			return ActionExecCommand(path, exportArgs...)(func(output []byte) Action {
+				return ActionImport(output)
+			}).Invoke(c).ToA()
+		}
+		if bridged {
+			return bridge.Invoke(c).ToA()
+		}
+		return ActionFiles()
+	})
+}
+
+// commandLineBoundary skips environment assignments, `--` and wrappers preceding the embedded command.
+// It returns false if the current value is the argument of a wrapper option.
+func commandLineBoundary(c *Context, args []string) ([]string, bool) {
+	for len(args) > 0 {
+		switch {
+		case envAssignment.MatchString(args[0]):
+			splitted := strings.SplitN(args[0], "=", 2)
+			c.Setenv(splitted[0], splitted[1])
+			args = args[1:]
+
+		case args[0] == "--":
+			args = args[1:]
+
+		default:
+			options, ok := commandLineWrappers[filepath.Base(args[0])]
+			if !ok {
+				return args, true
+			}
+
+			args = args[1:]
+		options:
+			for len(args) > 0 && strings.HasPrefix(args[0], "-") && args[0] != "-" {
+				option := args[0]
+				args = args[1:]
+				switch {
+				case option == "--":
+					break options
+				case containsString(options, option):
+					if len(args) == 0 {
+						return args, false
+					}
+					args = args[1:]
+				}
+			}
+		}
+	}
+	return args, true
+}
+
+var carapaceExecutables = struct {
+	sync.RWMutex
+	paths map[string]bool
+}{
+	paths: make(map[string]bool),
+}
+
+// carapaceExecutable resolves given executable (relative to `Context.Dir`)
+// and checks whether it was built with carapace.
+func carapaceExecutable(c Context, executable string) (string, bool) {
+	var path string
+	var err error
+	switch {
+	case strings.ContainsRune(executable, '/'):
+		path, err = c.Abs(executable)
+	default:
+		path, err = execlog.LookPath(executable)
+	}
+	if err != nil {
+		return "", false
+	}
+
+	carapaceExecutables.RLock()
+	result, cached := carapaceExecutables.paths[path]
+	carapaceExecutables.RUnlock()
+	if !cached {
+		result = isCarapaceExecutable(path)
+		carapaceExecutables.Lock()
+		carapaceExecutables.paths[path] = result
+		carapaceExecutables.Unlock()
+	}
+	return path, result
+}
+
+// isCarapaceExecutable checks whether the executable at given path was built with carapace.
+func isCarapaceExecutable(path string) bool {
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return false
+	}
+
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	if info.Main.Path == "github.com/carapace-sh/carapace" {
+		return true
+	}
+	for _, dep := range info.Deps {
+		switch dep.Path {
+		case "github.com/carapace-sh/carapace", "github.com/rsteube/carapace":
+			return true
+		}
+	}
+	return false
+}
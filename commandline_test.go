@@ -0,0 +1,70 @@
+package carapace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestActionCommandLine(t *testing.T) {
+	nestedCmd := &cobra.Command{Use: "nested", Run: func(cmd *cobra.Command, args []string) {}}
+	Gen(nestedCmd).PositionalCompletion(
+		ActionValues("one", "two"),
+	)
+	RegisterCommand(nestedCmd)
+
+	RegisterBridge("bridged", ActionCallback(func(c Context) Action {
+		return ActionValues(append(c.Args, c.Getenv("FOO"))...)
+	}))
+
+	assertEqual(t,
+		ActionValues("one").Invoke(Context{}),
+		ActionCommandLine().Invoke(Context{Args: []string{"nested"}, Value: "o"}),
+	)
+
+	assertEqual(t,
+		ActionValues("arg", "bar").Invoke(Context{}),
+		ActionCommandLine().Invoke(Context{Args: []string{"FOO=bar", "bridged", "arg"}}),
+	)
+
+	assertEqual(t,
+		ActionFiles().Invoke(Context{}),
+		ActionCommandLine().Invoke(Context{Args: []string{"unknown-command-line"}}),
+	)
+
+	assertEqual(t,
+		ActionValues("one").Invoke(Context{}),
+		ActionCommandLine().Invoke(Context{Args: []string{"--", "nested"}, Value: "o"}),
+	)
+
+	assertEqual(t,
+		ActionValues("arg", "bar").Invoke(Context{}),
+		ActionCommandLine().Invoke(Context{Args: []string{"sudo", "-u", "root", "--preserve-env", "env", "-i", "FOO=bar", "bridged", "arg"}}),
+	)
+
+	assertEqual(t,
+		ActionValues().Invoke(Context{}),
+		ActionCommandLine().Invoke(Context{Args: []string{"sudo", "-u"}}),
+	)
+}
+
+func TestCarapaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tool"), []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	path, ok := carapaceExecutable(Context{Dir: dir}, "./tool")
+	if expected := filepath.ToSlash(filepath.Join(dir, "tool")); path != expected || ok {
+		t.Errorf("expected %#v [was: %#v (%v)]", expected, path, ok)
+	}
+
+	carapaceExecutables.RLock()
+	_, cached := carapaceExecutables.paths[path]
+	carapaceExecutables.RUnlock()
+	if !cached {
+		t.Errorf("result for %#v should be cached", path)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -35,14 +36,14 @@ func registerFlagCompletion(cmd *cobra.Command) {
 			return cobraValuesFor(action), cobraDirectiveFor(action)
 		})
 		if err != nil {
-			LOG.Printf("failed to register flag completion func: %v", err.Error())
+			log.Logger.Warn("failed to register flag completion func", "flag", f.Name, "error", err.Error())
 		}
 	})
 }
 
 func cobraValuesFor(action InvokedAction) []string {
 	result := make([]string, len(action.action.rawValues))
-	for index, r := range action.action.rawValues {
+	for index, r := range action.action.rawValues.ApplyInsertSuffix() {
 		if r.Description != "" {
 			result[index] = fmt.Sprintf("%v\t%v", r.Value, r.Description)
 		} else {
@@ -55,11 +56,14 @@ func cobraValuesFor(action InvokedAction) []string {
 func cobraDirectiveFor(action InvokedAction) cobra.ShellCompDirective {
 	directive := cobra.ShellCompDirectiveNoFileComp
 	for _, val := range action.action.rawValues {
-		if action.action.meta.Nospace.Matches(val.Value) {
+		if val.IsNospace(action.action.meta.Nospace) {
 			directive = directive | cobra.ShellCompDirectiveNoSpace
 			break
 		}
 	}
+	if action.action.meta.KeepOrder {
+		directive = directive | cobra.ShellCompDirectiveKeepOrder
+	}
 	return directive
 }
 
@@ -105,5 +109,9 @@ func (d compDirective) ToA(values ...string) Action {
 		action = action.NoSpace()
 	}
 
+	if d.matches(cobra.ShellCompDirectiveKeepOrder) {
+		action = action.KeepOrder()
+	}
+
 	return action
 }
@@ -2,11 +2,14 @@ package carapace
 
 import (
 	"os"
+	"time"
 
 	"github.com/carapace-sh/carapace/internal/config"
+	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/carapace-sh/carapace/internal/shell/bash"
 	"github.com/carapace-sh/carapace/internal/shell/nushell"
 	"github.com/carapace-sh/carapace/pkg/ps"
+	"github.com/carapace-sh/carapace/pkg/settings"
 	"github.com/spf13/cobra"
 )
 
@@ -22,27 +25,40 @@ func complete(cmd *cobra.Command, args []string) (string, error) {
 		switch ps.DetermineShell() {
 		case "nushell":
 			args = nushell.Patch(args) // handle open quotes
-			LOG.Printf("patching args to %#v", args)
+			log.Logger.Debug("patched args", "args", args)
 		case "bash": // TODO what about oil and such?
-			LOG.Printf("COMP_LINE is %#v", os.Getenv("COMP_LINE"))
-			LOG.Printf("COMP_POINT is %#v", os.Getenv("COMP_POINT"))
+			log.Logger.Debug("bash environment", "COMP_LINE", os.Getenv("COMP_LINE"), "COMP_POINT", os.Getenv("COMP_POINT"))
 			var err error
 			args, err = bash.Patch(args) // handle redirects
-			LOG.Printf("patching args to %#v", args)
+			log.Logger.Debug("patched args", "args", args)
 			if err != nil {
 				context := NewContext(args...)
 				if _, ok := err.(bash.RedirectError); ok {
-					LOG.Printf("completing redirect target for %#v", args)
+					log.Logger.Debug("completing redirect target", "args", args)
 					return ActionFiles().Invoke(context).value(args[0], args[len(args)-1]), nil
 				}
 				return ActionMessage(err.Error()).Invoke(context).value(args[0], args[len(args)-1]), nil
 			}
 		}
 
+		if err := loadSettings(); err != nil {
+			log.Logger.Warn("ignoring invalid settings", "error", err.Error())
+		}
+		overlayErr := applyOverlay(cmd)
 		action, context := traverse(cmd, args[2:])
 		if err := config.Load(); err != nil {
-			action = ActionMessage("failed to load config: " + err.Error())
+			action = ActionMessage("failed to load config: %v", err.Error())
+		}
+		if overlayErr != nil {
+			action = ActionMessage("failed to load overlay: %v", overlayErr.Error())
 		}
-		return action.Invoke(context).value(args[0], args[len(args)-1]), nil
+		if timeout := settings.Carapace.Timeout; timeout > 0 {
+			action = action.Timeout(timeout, ActionMessage("timeout exceeded"))
+		}
+
+		start := time.Now()
+		invoked := action.Invoke(context)
+		log.Logger.Info("invoked action", "value", context.Value, "values", len(invoked.action.rawValues), "duration", time.Since(start))
+		return invoked.localize(context).value(args[0], args[len(args)-1]), nil
 	}
 }
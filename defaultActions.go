@@ -7,14 +7,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/carapace-sh/carapace/internal/common"
-	"github.com/carapace-sh/carapace/internal/config"
-	"github.com/carapace-sh/carapace/internal/env"
 	"github.com/carapace-sh/carapace/internal/export"
+	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/carapace-sh/carapace/internal/man"
+	"github.com/carapace-sh/carapace/pkg/i18n"
 	"github.com/carapace-sh/carapace/pkg/match"
+	"github.com/carapace-sh/carapace/pkg/settings"
 	"github.com/carapace-sh/carapace/pkg/style"
 	"github.com/carapace-sh/carapace/third_party/github.com/acarl005/stripansi"
 	"github.com/spf13/cobra"
@@ -102,6 +104,44 @@ func ActionImport(output []byte) Action {
 	})
 }
 
+// ActionImportCobra parses the output of cobra's hidden `__complete` command as Action.
+//
+//	carapace.ActionExecCommand("kubectl", "__complete", "get", "")(func(output []byte) carapace.Action {
+//		return carapace.ActionImportCobra(output)
+//	})
+func ActionImportCobra(output []byte) Action {
+	return ActionCallback(func(c Context) Action {
+		lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+		last := lines[len(lines)-1]
+		if !strings.HasPrefix(last, ":") {
+			return ActionMessage("missing directive in cobra completion output")
+		}
+
+		directive, err := strconv.Atoi(last[1:])
+		if err != nil {
+			return ActionMessage(err.Error())
+		}
+
+		values := make([]string, 0)
+		activeHelp := make([]string, 0)
+		for _, line := range lines[:len(lines)-1] {
+			switch {
+			case strings.HasPrefix(line, "_activeHelp_ "):
+				activeHelp = append(activeHelp, strings.TrimPrefix(line, "_activeHelp_ "))
+			case line != "":
+				values = append(values, line)
+			}
+		}
+
+		// attach active help to the same action as merging a batch would sort the values (ShellCompDirectiveKeepOrder)
+		invoked := compDirective(directive).ToA(values...).Invoke(c)
+		for _, help := range activeHelp {
+			invoked.action.meta.Messages.AddSeverity(stripansi.Strip(help), common.SeverityInfo)
+		}
+		return invoked.ToA()
+	})
+}
+
 // ActionExecute executes completion on an internal command
 // TODO example.
 func ActionExecute(cmd *cobra.Command) Action {
@@ -134,14 +174,14 @@ func ActionExecute(cmd *cobra.Command) Action {
 func ActionDirectories() Action {
 	return ActionCallback(func(c Context) Action {
 		return actionPath([]string{""}, true).Invoke(c).ToMultiPartsA("/").StyleF(style.ForPath)
-	}).Tag("directories")
+	}).Tag("directories").withSpec("$directories")
 }
 
 // ActionFiles completes files with optional suffix filtering.
 func ActionFiles(suffix ...string) Action {
 	return ActionCallback(func(c Context) Action {
 		return actionPath(suffix, false).Invoke(c).ToMultiPartsA("/").StyleF(style.ForPath)
-	}).Tag("files")
+	}).Tag("files").withSpec(specMacro("files", suffix...))
 }
 
 // ActionValues completes arbitrary keywords (values).
@@ -154,7 +194,7 @@ func ActionValues(values ...string) Action {
 			}
 		}
 		return Action{rawValues: vals}
-	})
+	}).withSpec(specValues(1, 0, values...)...)
 }
 
 // ActionStyledValues is like ActionValues but also accepts a style.
@@ -169,7 +209,7 @@ func ActionStyledValues(values ...string) Action {
 			vals = append(vals, common.RawValue{Value: values[i], Display: values[i], Style: values[i+1]})
 		}
 		return Action{rawValues: vals}
-	})
+	}).withSpec(specValues(2, 0, values...)...)
 }
 
 // ActionValuesDescribed completes arbitrary key (values) with an additional description (value, description pairs).
@@ -184,7 +224,7 @@ func ActionValuesDescribed(values ...string) Action {
 			vals = append(vals, common.RawValue{Value: values[i], Display: values[i], Description: values[i+1]})
 		}
 		return Action{rawValues: vals}
-	})
+	}).withSpec(specValues(2, 1, values...)...)
 }
 
 // ActionStyledValuesDescribed is like ActionValues but also accepts a style.
@@ -199,17 +239,36 @@ func ActionStyledValuesDescribed(values ...string) Action {
 			vals = append(vals, common.RawValue{Value: values[i], Display: values[i], Description: values[i+1], Style: values[i+2]})
 		}
 		return Action{rawValues: vals}
-	})
+	}).withSpec(specValues(3, 1, values...)...)
 }
 
 // ActionMessage displays a help messages in places where no completions can be generated.
 func ActionMessage(msg string, args ...interface{}) Action {
+	return actionMessage(common.SeverityError, msg, args...)
+}
+
+// ActionWarning is like ActionMessage but with severity warning.
+//
+//	carapace.ActionWarning("no remotes configured")
+func ActionWarning(msg string, args ...interface{}) Action {
+	return actionMessage(common.SeverityWarning, msg, args...)
+}
+
+// ActionInfo is like ActionMessage but with severity info.
+//
+//	carapace.ActionInfo("use --all to show archived projects")
+func ActionInfo(msg string, args ...interface{}) Action {
+	return actionMessage(common.SeverityInfo, msg, args...)
+}
+
+func actionMessage(severity common.Severity, msg string, args ...interface{}) Action {
 	return ActionCallback(func(c Context) Action {
+		message := i18n.Translate(i18n.Language(c.Getenv), msg)
 		if len(args) > 0 {
-			msg = fmt.Sprintf(msg, args...)
+			message = fmt.Sprintf(message, args...)
 		}
 		a := ActionValues()
-		a.meta.Messages.Add(stripansi.Strip(msg))
+		a.meta.Messages.AddSeverity(stripansi.Strip(message), severity)
 		return a
 	})
 }
@@ -274,26 +333,7 @@ func ActionStyleConfig() Action {
 	return ActionMultiParts("=", func(c Context) Action {
 		switch len(c.Parts) {
 		case 0:
-			return ActionMultiParts(".", func(c Context) Action {
-				switch len(c.Parts) {
-				case 0:
-					return ActionValues(config.GetStyleConfigs()...).Invoke(c).Suffix(".").ToA()
-
-				case 1:
-					fields, err := config.GetStyleFields(c.Parts[0])
-					if err != nil {
-						return ActionMessage(err.Error())
-					}
-					batch := Batch()
-					for _, field := range fields {
-						batch = append(batch, ActionStyledValuesDescribed(field.Name, field.Description, field.Style).Tag(field.Tag))
-					}
-					return batch.Invoke(c).Merge().Suffix("=").ToA()
-
-				default:
-					return ActionValues()
-				}
-			})
+			return actionStyleKeys("=")
 		case 1:
 			return ActionMultiParts(",", func(c Context) Action {
 				return ActionStyles(c.Parts...).Invoke(c).Filter(c.Parts...).ToA().NoSpace()
@@ -436,7 +476,7 @@ func ActionExecutables() Action {
 			batch = append(batch, actionDirectoryExecutables(dirs[i], c.Value, manDescriptions))
 		}
 		return batch.ToA()
-	}).Tag("executables")
+	}).Tag("executables").withSpec("$executables")
 }
 
 func actionDirectoryExecutables(dir string, prefix string, manDescriptions map[string]string) Action {
@@ -508,7 +548,7 @@ func ActionCommands(cmd *cobra.Command) Action {
 
 		batch := Batch()
 		for _, subcommand := range cmd.Commands() {
-			if (!subcommand.Hidden || env.Hidden()) && subcommand.Deprecated == "" {
+			if (!subcommand.Hidden || settings.Carapace.Hidden) && subcommand.Deprecated == "" {
 				group := common.Group{Cmd: subcommand}
 				batch = append(batch, ActionStyledValuesDescribed(subcommand.Name(), subcommand.Short, group.Style()).Tag(group.Tag()))
 				for _, alias := range subcommand.Aliases {
@@ -527,7 +567,7 @@ func ActionCobra(f func(cmd *cobra.Command, args []string, toComplete string) ([
 		case f == nil:
 			return ActionValues()
 		case c.cmd == nil: // ensure cmd is never nil even if context does not contain one
-			LOG.Print("cmd is nil [ActionCobra]")
+			log.Logger.Debug("cmd is nil", "action", "ActionCobra")
 			c.cmd = &cobra.Command{Use: "_carapace_actioncobra", Hidden: true, Deprecated: "dummy command for ActionCobra"}
 		}
 
@@ -32,6 +32,34 @@ func TestActionImport(t *testing.T) {
 	assertEqual(t, ActionValues("positional1", "p1").Tag("first").Invoke(Context{}), ActionImport([]byte(s)).Invoke(Context{}))
 }
 
+func TestActionImportCobra(t *testing.T) {
+	s := "one\tfirst\ntwo\n_activeHelp_ some help\n:6\n"
+	assertEqual(t,
+		Batch(
+			ActionValuesDescribed("one", "first", "two", "").NoSpace(),
+			ActionInfo("some help"),
+		).ToA().Invoke(Context{}),
+		ActionImportCobra([]byte(s)).Invoke(Context{}),
+	)
+
+	s = "two\none\n_activeHelp_ some help\n:32\n"
+	assertEqual(t,
+		Batch(
+			ActionValues("two", "one").KeepOrder(),
+			ActionInfo("some help"),
+		).ToA().Invoke(Context{}),
+		ActionImportCobra([]byte(s)).Invoke(Context{}),
+	)
+	if values := ActionImportCobra([]byte(s)).Invoke(Context{}).action.rawValues; len(values) != 2 || values[0].Value != "two" {
+		t.Errorf("order should be kept with active help: %#v", values)
+	}
+
+	assertEqual(t,
+		ActionMessage("missing directive in cobra completion output").Invoke(Context{}),
+		ActionImportCobra([]byte("one\n")).Invoke(Context{}),
+	)
+}
+
 func TestActionFlags(t *testing.T) {
 	cmd := &cobra.Command{}
 	cmd.Flags().BoolP("alpha", "a", false, "")
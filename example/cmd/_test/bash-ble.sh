@@ -41,7 +41,11 @@ _example_completion_ble() {
 
     local cand
     for cand in "${c[@]}"; do
-      [ ! -z "$cand" ] && ble/complete/cand/yield mandb "${cand%$'\t'*}" "${cand##*$'\t'}"
+      if [[ $cand == $'\001'* ]]; then
+        ble/util/print "${cand#$'\001'}" >&2
+      elif [ ! -z "$cand" ]; then
+        ble/complete/cand/yield mandb "${cand%$'\t'*}" "${cand##*$'\t'}"
+      fi
     done
   else
     complete -F _example_completion example
@@ -11,7 +11,13 @@ function _example_quote_suffix
 end
 
 function _example_callback
-  commandline -cp | sed "s/\$/"(_example_quote_suffix)"/" | sed "s/ \$/ ''/" | xargs example _carapace fish
+  set -l lines (commandline -cp | sed "s/\$/"(_example_quote_suffix)"/" | sed "s/ \$/ ''/" | xargs example _carapace fish)
+  set -l messages (string replace -r -f -- '^\x01' '' $lines)
+  if set -q messages[1]
+    printf '\n%s' $messages >&2
+    commandline -f repaint
+  end
+  string match -v -r -- '^\x01' $lines
 end
 
 complete -c example -f
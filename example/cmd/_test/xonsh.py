@@ -14,9 +14,15 @@ def _example_completer(context):
 
         output, _ = Popen(['example', '_carapace', 'xonsh', *[a.value for a in context.args], fix_prefix(context.prefix)], stdout=PIPE, stderr=PIPE).communicate()
         try:
-            result = {RichCompletion(c["Value"], display=c["Display"], description=c["Description"], prefix_len=len(context.raw_prefix), append_closing_quote=False, style=c["Style"]) for c in loads(output)}
+            entries = loads(output)
+            result = {RichCompletion(c["Value"], display=c["Display"], description=c["Description"], prefix_len=len(context.raw_prefix), append_closing_quote=False, style=c["Style"]) for c in entries if "Message" not in c}
         except:
+            entries = []
             result = {}
+        messages = [c["Message"] for c in entries if c.get("Message")]
+        if len(messages) > 0:
+            from sys import stderr
+            print('\n' + '\n'.join(messages), file=stderr)
         if len(result) == 0:
             result = {RichCompletion(context.prefix, display=context.prefix, description='', prefix_len=len(context.raw_prefix), append_closing_quote=False)}
         return result
@@ -0,0 +1,4 @@
+completion/example() {
+  eval "$(example _carapace yash "${WORDS}" "${TARGETWORD}" 2>/dev/null)"
+}
+
@@ -18,14 +18,17 @@ function _example_completion {
   zstyle ":completion:${curcontext}:*" group-name ''
   [ -z "$message" ] || _message -r "${message}"
   
-  local block tag displays values displaysArr valuesArr
+  local block tag suffix remove displays values displaysArr valuesArr options
   while IFS=$'\002' read -r -d $'\002' block; do
-    IFS=$'\003' read -r -d '' tag displays values <<<"${block}"
+    IFS=$'\003' read -r -d '' tag suffix remove displays values <<<"${block}"
     # shellcheck disable=SC2034
     IFS=$'\n' read -r -d $'\004' -A displaysArr <<<"${displays}"$'\004'
     IFS=$'\n' read -r -d $'\004' -A valuesArr <<<"${values}"$'\004'
   
-    [[ ${#valuesArr[@]} -gt 1 ]] && _describe -t "${tag}" "${tag}" displaysArr valuesArr -Q -S ''
+    options=(-Q -S "${suffix}")
+    [ -z "${remove}" ] || options+=(-r "${remove}")
+  
+    [[ ${#valuesArr[@]} -gt 1 ]] && _describe -t "${tag}" "${tag}" displaysArr valuesArr "${options[@]}"
   done <<<"${data}"
 }
 compquote '' 2>/dev/null && _example_completion
@@ -62,9 +62,10 @@ func TestCompat(t *testing.T) {
 		s.Run("compat", "--keeporder", "").
 			Expect(carapace.ActionValues(
 				"one",
-				"two",
 				"three",
-			).Usage("ShellCompDirectiveKeepOrder"))
+				"two",
+			).KeepOrder().
+				Usage("ShellCompDirectiveKeepOrder"))
 
 		s.Run("compat", "--default", "").
 			Expect(carapace.ActionValues(
@@ -36,6 +36,14 @@ func TestFish(t *testing.T) {
 	testScript(t, "fish", "./_test/fish.fish")
 }
 
+func TestKsh(t *testing.T) {
+	testScript(t, "ksh", "./_test/ksh.sh")
+}
+
+func TestMurex(t *testing.T) {
+	testScript(t, "murex", "./_test/murex.mx")
+}
+
 func TestNushell(t *testing.T) {
 	testScript(t, "nushell", "./_test/nushell.nu")
 }
@@ -52,6 +60,10 @@ func TestXonsh(t *testing.T) {
 	testScript(t, "xonsh", "./_test/xonsh.py")
 }
 
+func TestYash(t *testing.T) {
+	testScript(t, "yash", "./_test/yash.sh")
+}
+
 func TestZsh(t *testing.T) {
 	testScript(t, "zsh", "./_test/zsh.sh")
 }
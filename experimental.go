@@ -5,6 +5,7 @@ import (
 
 	"github.com/carapace-sh/carapace/internal/config"
 	"github.com/carapace-sh/carapace/internal/export"
+	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/carapace-sh/carapace/pkg/x"
 	"github.com/spf13/cobra"
 )
@@ -16,13 +17,19 @@ func init() {
 
 	x.Complete = func(cmd *cobra.Command, args ...string) (*export.Export, error) {
 		initHelpCompletion(cmd)
+		if err := loadSettings(); err != nil {
+			log.Logger.Warn("ignoring invalid settings", "error", err.Error())
+		}
+		if err := applyOverlay(cmd); err != nil {
+			return nil, err
+		}
 		action, context := traverse(cmd, args[2:])
 
 		if err := config.Load(); err != nil {
 			return nil, err
 		}
 
-		output := action.Invoke(context).value("export", "")
+		output := action.Invoke(context).localize(context).value("export", "")
 		var e export.Export
 		if err := json.Unmarshal([]byte(output), &e); err != nil {
 			return nil, err
@@ -7,14 +7,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/carapace-sh/carapace/internal/env"
 	"github.com/carapace-sh/carapace/internal/export"
 	"github.com/carapace-sh/carapace/internal/uid"
 	"github.com/carapace-sh/carapace/pkg/cache/key"
+	"github.com/carapace-sh/carapace/pkg/settings"
 	"github.com/carapace-sh/carapace/pkg/xdg"
 )
 
@@ -26,8 +30,48 @@ func WriteE(file string, e export.Export) (err error) {
 	return
 }
 
+var pruneOnce sync.Once
+
 func Write(file string, content []byte) (err error) {
-	return os.WriteFile(file, content, 0600)
+	if err = os.WriteFile(file, content, 0600); err == nil {
+		if budget := settings.Carapace.CacheBudget; budget > 0 {
+			pruneOnce.Do(func() { // walking the cache once per invocation is sufficient
+				if dir, err := executableDir(); err == nil {
+					prune(dir, int64(budget)*1024*1024)
+				}
+			})
+		}
+	}
+	return
+}
+
+// prune removes the least recently modified files in given directory until its size is within budget.
+func prune(dir string, budget int64) {
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	entries := make([]entry, 0)
+	var size int64
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			entries = append(entries, entry{path, info.Size(), info.ModTime()})
+			size += info.Size()
+		}
+		return nil
+	})
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	for _, e := range entries {
+		if size <= budget {
+			return
+		}
+		if os.Remove(e.path) == nil {
+			size -= e.size
+		}
+	}
 }
 
 func LoadE(file string, timeout time.Duration) (*export.Export, error) { // TODO reference
@@ -53,19 +97,26 @@ func Load(file string, timeout time.Duration) (b []byte, err error) {
 
 // CacheDir creates a cache folder for current user and returns the path.
 func CacheDir(name string) (dir string, err error) {
-	var userCacheDir string
-	userCacheDir, err = xdg.UserCacheDir()
-	if err != nil {
+	if dir, err = executableDir(); err != nil {
 		return
 	}
 
+	dir = filepath.Join(dir, name)
+	err = os.MkdirAll(dir, 0700)
+	return
+}
+
+// executableDir returns the cache directory of the current executable.
+func executableDir() (string, error) {
+	userCacheDir, err := xdg.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
 	if m, sandboxErr := env.Sandbox(); sandboxErr == nil {
 		userCacheDir = m.CacheDir()
 	}
-
-	dir = fmt.Sprintf("%v/carapace/%v/%v", userCacheDir, uid.Executable(), name)
-	err = os.MkdirAll(dir, 0700)
-	return
+	return filepath.Join(userCacheDir, "carapace", uid.Executable()), nil
 }
 
 // File returns the cache filename for given values
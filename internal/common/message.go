@@ -10,24 +10,110 @@ import (
 	"github.com/carapace-sh/carapace/pkg/style"
 )
 
+// Severity defines how important a message is.
+type Severity int
+
+const (
+	SeverityInfo    Severity = iota // hint for the user
+	SeverityWarning                 // something might not work as expected
+	SeverityError                   // something failed
+)
+
+// String returns the lowercase name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// Label returns the short uppercase label used for integrated messages.
+func (s Severity) Label() string {
+	switch s {
+	case SeverityInfo:
+		return "INFO"
+	case SeverityWarning:
+		return "WARN"
+	default:
+		return "ERR"
+	}
+}
+
+// Style returns the configured style for the severity.
+func (s Severity) Style() string {
+	switch s {
+	case SeverityInfo:
+		return style.Carapace.Info
+	case SeverityWarning:
+		return style.Carapace.Warning
+	default:
+		return style.Carapace.Error
+	}
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "info":
+		*s = SeverityInfo
+	case "warning":
+		*s = SeverityWarning
+	case "error", "":
+		*s = SeverityError
+	default:
+		return fmt.Errorf("unknown severity: '%v'", string(text))
+	}
+	return nil
+}
+
+// Message is a message with its severity.
+type Message struct {
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity"`
+}
+
 type Messages struct {
-	messages map[string]bool
+	messages map[string]Severity
 }
 
 func (m *Messages) init() {
 	if m.messages == nil {
-		m.messages = make(map[string]bool)
+		m.messages = make(map[string]Severity)
 	}
 }
 
 func (m Messages) IsEmpty() bool {
-	// TODO replacement for Action.skipCache - does this need to consider suppressed messages or is this fine?
 	return len(m.messages) == 0
 }
 
+// HasErrors checks if any message has SeverityError.
+func (m Messages) HasErrors() bool {
+	for _, severity := range m.messages {
+		if severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds an error message.
 func (m *Messages) Add(s string) {
+	m.AddSeverity(s, SeverityError)
+}
+
+// AddSeverity adds a message with given severity (the highest one is kept for duplicates).
+func (m *Messages) AddSeverity(s string, severity Severity) {
 	m.init()
-	m.messages[s] = true
+	if existing, ok := m.messages[s]; !ok || existing < severity {
+		m.messages[s] = severity
+	}
 }
 
 func (m Messages) Get() []string {
@@ -39,7 +125,32 @@ func (m Messages) Get() []string {
 	return messages
 }
 
+// List returns the messages sorted by severity (highest first) and text.
+func (m Messages) List() []Message {
+	messages := make([]Message, 0, len(m.messages))
+	for message, severity := range m.messages {
+		messages = append(messages, Message{Message: message, Severity: severity})
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if messages[i].Severity != messages[j].Severity {
+			return messages[i].Severity > messages[j].Severity
+		}
+		return messages[i].Message < messages[j].Message
+	})
+	return messages
+}
+
+// Suppress removes messages of any severity matching given regular expressions.
 func (m *Messages) Suppress(expr ...string) error {
+	return m.suppress(func(Severity) bool { return true }, expr...)
+}
+
+// SuppressSeverity removes messages of given severity matching given regular expressions.
+func (m *Messages) SuppressSeverity(severity Severity, expr ...string) error {
+	return m.suppress(func(s Severity) bool { return s == severity }, expr...)
+}
+
+func (m *Messages) suppress(f func(s Severity) bool, expr ...string) error {
 	m.init()
 
 	for _, e := range expr {
@@ -48,8 +159,8 @@ func (m *Messages) Suppress(expr ...string) error {
 			return err
 		}
 
-		for key := range m.messages {
-			if r.MatchString(key) {
+		for key, severity := range m.messages {
+			if f(severity) && r.MatchString(key) {
 				delete(m.messages, key)
 			}
 		}
@@ -62,9 +173,18 @@ func (m *Messages) Merge(other Messages) {
 		return
 	}
 
-	for key := range other.messages {
-		m.Add(key)
+	for key, severity := range other.messages {
+		m.AddSeverity(key, severity)
+	}
+}
+
+// Translate returns a copy with each message passed through f.
+func (m Messages) Translate(f func(s string) string) Messages {
+	translated := Messages{}
+	for message, severity := range m.messages {
+		translated.AddSeverity(f(message), severity)
 	}
+	return translated
 }
 
 func (m Messages) Integrate(values RawValues, prefix string) RawValues {
@@ -74,29 +194,22 @@ func (m Messages) Integrate(values RawValues, prefix string) RawValues {
 		return values
 	}
 
-	sorted := make([]string, 0)
-	for message := range m.messages {
-		sorted = append(sorted, message)
-	}
-	sort.Strings(sorted)
-
-	switch {
-	case strings.HasSuffix(prefix, "ERR"):
-		prefix = strings.TrimSuffix(prefix, "ERR")
-	case strings.HasSuffix(prefix, "ER"):
-		prefix = strings.TrimSuffix(prefix, "ER")
-	case strings.HasSuffix(prefix, "E"):
-		prefix = strings.TrimSuffix(prefix, "E")
+	for _, label := range []string{SeverityError.Label(), SeverityWarning.Label(), SeverityInfo.Label()} {
+		if trimmed := trimPartialSuffix(prefix, label); trimmed != prefix {
+			prefix = trimmed
+			break
+		}
 	}
 
 	i := 0
-	for _, message := range sorted {
-		value := prefix + "ERR"
-		display := "ERR"
+	for _, message := range m.List() {
+		label := message.Severity.Label()
+		value := prefix + label
+		display := label
 		for {
 			if i > 0 {
-				value = fmt.Sprintf("%vERR%v", prefix, i)
-				display = fmt.Sprintf("ERR%v", i)
+				value = fmt.Sprintf("%v%v%v", prefix, label, i)
+				display = fmt.Sprintf("%v%v", label, i)
 			}
 			i += 1
 
@@ -108,8 +221,9 @@ func (m Messages) Integrate(values RawValues, prefix string) RawValues {
 		values = append(values, RawValue{
 			Value:       value,
 			Display:     display,
-			Description: message,
-			Style:       style.Carapace.Error,
+			Description: message.Message,
+			Style:       message.Severity.Style(),
+			Nospace:     true,
 		})
 	}
 
@@ -119,28 +233,54 @@ func (m Messages) Integrate(values RawValues, prefix string) RawValues {
 			Display:     "_",
 			Description: "",
 			Style:       style.Default,
+			Nospace:     true,
 		})
 	}
 	sort.Sort(ByDisplay(values))
 	return values
 }
 
+// trimPartialSuffix removes the longest prefix of label that is a suffix of s.
+func trimPartialSuffix(s, label string) string {
+	for i := len(label); i > 0; i-- {
+		if strings.HasSuffix(s, label[:i]) {
+			return strings.TrimSuffix(s, label[:i])
+		}
+	}
+	return s
+}
+
+// MarshalJSON exports errors as plain strings (compatible with older versions) and other severities as Message.
 func (m Messages) MarshalJSON() ([]byte, error) {
-	var result []string = make([]string, 0, len(m.messages))
-	for key := range m.messages {
-		result = append(result, key)
+	result := make([]interface{}, 0, len(m.messages))
+	for _, message := range m.List() {
+		switch message.Severity {
+		case SeverityError:
+			result = append(result, message.Message)
+		default:
+			result = append(result, message)
+		}
 	}
-	sort.Strings(result)
 	return json.Marshal(&result)
 }
 
 func (m *Messages) UnmarshalJSON(data []byte) (err error) {
-	var result []string
+	var result []json.RawMessage
 	if err = json.Unmarshal(data, &result); err != nil {
 		return err
 	}
 	for _, item := range result {
-		m.Add(item)
+		var s string
+		if err := json.Unmarshal(item, &s); err == nil {
+			m.Add(s)
+			continue
+		}
+
+		var message Message
+		if err := json.Unmarshal(item, &message); err != nil {
+			return err
+		}
+		m.AddSeverity(message.Message, message.Severity)
 	}
 	return
 }
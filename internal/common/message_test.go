@@ -0,0 +1,54 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMessagesJSON(t *testing.T) {
+	m := Messages{}
+	m.Add("an error")
+	m.AddSeverity("a warning", SeverityWarning)
+	m.AddSeverity("an info", SeverityInfo)
+
+	marshalled, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := `["an error",{"message":"a warning","severity":"warning"},{"message":"an info","severity":"info"}]`
+	if string(marshalled) != expected {
+		t.Errorf("should be %v [was: %v]", expected, string(marshalled))
+	}
+
+	var unmarshalled Messages
+	if err := json.Unmarshal(marshalled, &unmarshalled); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(unmarshalled.List()) != 3 || !unmarshalled.HasErrors() {
+		t.Errorf("unmarshalled messages should match: %#v", unmarshalled.List())
+	}
+}
+
+func TestMessagesMergeSeverity(t *testing.T) {
+	m := Messages{}
+	m.AddSeverity("message", SeverityInfo)
+
+	other := Messages{}
+	other.Add("message")
+	m.Merge(other)
+
+	if !m.HasErrors() {
+		t.Error("higher severity should be kept")
+	}
+}
+
+func TestMessagesIntegrate(t *testing.T) {
+	m := Messages{}
+	m.AddSeverity("a warning", SeverityWarning)
+
+	values := m.Integrate(RawValues{}, "WA")
+	if len(values) != 2 || values[0].Value != "WARN" || values[1].Value != "_" {
+		t.Errorf("should contain WARN and _ [was: %#v]", values)
+	}
+}
@@ -1,15 +1,17 @@
 package common
 
 type Meta struct {
-	Messages Messages      `json:"messages"`
-	Nospace  SuffixMatcher `json:"nospace"`
-	Usage    string        `json:"usage"`
+	Messages  Messages      `json:"messages"`
+	Nospace   SuffixMatcher `json:"nospace"`
+	Usage     string        `json:"usage"`
+	KeepOrder bool          `json:"keepOrder,omitempty"`
 }
 
 func (m *Meta) Merge(other Meta) {
 	if other.Usage != "" {
 		m.Usage = other.Usage
 	}
+	m.KeepOrder = m.KeepOrder || other.KeepOrder
 	m.Nospace.Merge(other.Nospace)
 	m.Messages.Merge(other.Messages)
 }
@@ -20,17 +20,85 @@ type RawValue struct {
 	Description string `json:"description,omitempty"`
 	Style       string `json:"style,omitempty"`
 	Tag         string `json:"tag,omitempty"`
+	// Nospace disables the space suffix for this value.
+	Nospace bool `json:"nospace,omitempty"`
+	// InsertSuffix is inserted after the value instead of a space (zsh `-S`).
+	InsertSuffix string `json:"insertSuffix,omitempty"`
+	// RemoveChars removes InsertSuffix when one of these characters is typed next (zsh `-r`).
+	RemoveChars string `json:"removeChars,omitempty"`
 }
 
-// TrimmedDescription returns the trimmed description.
+// IsNospace checks if the value should not be followed by a space (falls back to given suffix matcher).
+func (r RawValue) IsNospace(sm SuffixMatcher) bool {
+	return r.Nospace || r.InsertSuffix != "" || sm.Matches(r.Value)
+}
+
+var (
+	// DescriptionWidth is the maximum width of trimmed descriptions (derived from TerminalColumns if 0).
+	DescriptionWidth = 0
+	// TerminalColumns is the width of the terminal (0 if unknown).
+	TerminalColumns = 0
+)
+
+const (
+	defaultDescriptionWidth = 80
+	minDescriptionWidth     = 20
+	ellipsis                = "..."
+)
+
+// descriptionWidth returns the maximum width of the description for given display value.
+func descriptionWidth(display string) int {
+	switch {
+	case DescriptionWidth > 0:
+		return DescriptionWidth
+	case TerminalColumns > 0:
+		width := TerminalColumns - StringWidth(display) - 4 // space and parentheses around description
+		if width < minDescriptionWidth {
+			return minDescriptionWidth
+		}
+		return width
+	default:
+		return defaultDescriptionWidth
+	}
+}
+
+// TrimmedDescription returns the first line of the description trimmed to the maximum width.
 func (r RawValue) TrimmedDescription() string {
-	maxLength := 80
 	description := strings.SplitN(r.Description, "\n", 2)[0]
 	description = strings.TrimSpace(description)
-	if len([]rune(description)) > maxLength {
-		description = string([]rune(description)[:maxLength-3]) + "..."
+	return truncate(description, descriptionWidth(r.Display))
+}
+
+// MultilineDescription returns the full description for shells that can show multiple lines.
+func (r RawValue) MultilineDescription() string {
+	lines := strings.Split(strings.ReplaceAll(r.Description, "\r", ""), "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// truncate shortens s to given width placing the ellipsis at a word boundary if possible.
+func truncate(s string, width int) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+
+	runes := make([]rune, 0)
+	current := 0
+	for _, r := range s {
+		if current+RuneWidth(r) > width-len(ellipsis) {
+			break
+		}
+		runes = append(runes, r)
+		current += RuneWidth(r)
 	}
-	return description
+
+	truncated := string(runes)
+	if index := strings.LastIndexAny(truncated, " \t"); index > len(truncated)/2 {
+		truncated = truncated[:index] // cut at word boundary unless it removes too much
+	}
+	return strings.TrimRight(truncated, " \t") + ellipsis
 }
 
 // RawValues is an alias for []RawValue.
@@ -108,6 +176,26 @@ func (r RawValues) Decolor() RawValues {
 	return rawValues
 }
 
+// Downgrade maps the colors of the styles to given color depth.
+func (r RawValues) Downgrade(depth style.Depth) RawValues {
+	rawValues := make(RawValues, len(r))
+	for index, value := range r {
+		value.Style = style.Downgrade(value.Style, depth)
+		rawValues[index] = value
+	}
+	return rawValues
+}
+
+// Translate passes the descriptions through f.
+func (r RawValues) Translate(f func(s string) string) RawValues {
+	rawValues := make(RawValues, len(r))
+	for index, value := range r {
+		value.Description = f(value.Description)
+		rawValues[index] = value
+	}
+	return rawValues
+}
+
 // FilterPrefix filters values with given prefix.
 func (r RawValues) FilterPrefix(prefix string) RawValues {
 	filtered := make(RawValues, 0)
@@ -119,6 +207,21 @@ func (r RawValues) FilterPrefix(prefix string) RawValues {
 	return filtered
 }
 
+// ApplyInsertSuffix appends InsertSuffix to the value for shells without native support.
+func (r RawValues) ApplyInsertSuffix() RawValues {
+	rawValues := make(RawValues, len(r))
+	for index, value := range r {
+		if value.InsertSuffix != "" {
+			value.Value += value.InsertSuffix
+			value.Nospace = true
+			value.InsertSuffix = ""
+			value.RemoveChars = ""
+		}
+		rawValues[index] = value
+	}
+	return rawValues
+}
+
 func (r RawValues) EachTag(f func(tag string, values RawValues)) {
 	tagGroups := make(map[string]RawValues)
 	for _, val := range r {
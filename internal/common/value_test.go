@@ -14,6 +14,66 @@ func TestTrimmedDescription(t *testing.T) {
 	}
 }
 
+func TestTrimmedDescriptionWordBoundary(t *testing.T) {
+	defer func() { DescriptionWidth = 0 }()
+	DescriptionWidth = 20
+
+	r := RawValue{Description: "some rather long description\nsecond line"}
+	if actual := r.TrimmedDescription(); actual != "some rather long..." {
+		t.Errorf("unexpected description: %#v", actual)
+	}
+}
+
+func TestTrimmedDescriptionWide(t *testing.T) {
+	defer func() { DescriptionWidth = 0 }()
+	DescriptionWidth = 10
+
+	r := RawValue{Description: "日本語の説明文です"}
+	if actual := r.TrimmedDescription(); actual != "日本語..." {
+		t.Errorf("unexpected description: %#v", actual)
+	}
+}
+
+func TestTrimmedDescriptionColumns(t *testing.T) {
+	defer func() { TerminalColumns = 0 }()
+	TerminalColumns = 30
+
+	r := RawValue{Display: "display", Description: "abcdefghijklmnopqrstuvwxyz"}
+	if actual := r.TrimmedDescription(); actual != "abcdefghijklmnopq..." {
+		t.Errorf("unexpected description: %#v", actual)
+	}
+}
+
+func TestMultilineDescription(t *testing.T) {
+	r := RawValue{Description: " first  \r\nsecond\t\n"}
+	if actual := r.MultilineDescription(); actual != "first\nsecond" {
+		t.Errorf("unexpected description: %#v", actual)
+	}
+}
+
+func TestIsNospace(t *testing.T) {
+	sm := SuffixMatcher{"/"}
+	if !(RawValue{Value: "dir/"}).IsNospace(sm) {
+		t.Error("suffix matcher should apply")
+	}
+	if !(RawValue{Value: "HEAD", Nospace: true}).IsNospace(sm) {
+		t.Error("nospace should apply")
+	}
+	if !(RawValue{Value: "key", InsertSuffix: "="}).IsNospace(sm) {
+		t.Error("insert suffix should replace space")
+	}
+	if (RawValue{Value: "HEAD"}).IsNospace(sm) {
+		t.Error("space expected")
+	}
+}
+
+func TestApplyInsertSuffix(t *testing.T) {
+	v := RawValues{{Value: "key", Display: "key", InsertSuffix: "=", RemoveChars: " "}}.ApplyInsertSuffix()
+	if v[0] != (RawValue{Value: "key=", Display: "key", Nospace: true}) {
+		t.Errorf("unexpected value: %#v", v[0])
+	}
+}
+
 func TestRawValuesFrom(t *testing.T) {
 	v := RawValuesFrom("first", "second")
 	if !equalRawValues(v[0], RawValue{
@@ -0,0 +1,48 @@
+package common
+
+import "unicode"
+
+// wide contains ranges of east asian wide and fullwidth characters (two columns).
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1}, // hangul jamo
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1}, // cjk radicals, kangxi, cjk symbols
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1}, // hiragana, katakana, bopomofo, ...
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1}, // cjk unified ideographs extension a
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1}, // cjk unified ideographs
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1}, // yi
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1}, // hangul syllables
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1}, // cjk compatibility ideographs
+		{Lo: 0xfe30, Hi: 0xfe4f, Stride: 1}, // cjk compatibility forms
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1}, // fullwidth forms
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1}, // fullwidth signs
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1}, // pictographs, emoticons
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1}, // supplemental symbols and pictographs
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 1}, // cjk unified ideographs extension b-f
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 1}, // cjk unified ideographs extension g
+	},
+}
+
+// RuneWidth returns the amount of terminal columns needed for given rune.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0x200d, r >= 0xfe00 && r <= 0xfe0f: // zero width joiner, variation selectors
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case unicode.Is(wide, r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// StringWidth returns the amount of terminal columns needed for given string.
+func StringWidth(s string) (width int) {
+	for _, r := range s {
+		width += RuneWidth(r)
+	}
+	return
+}
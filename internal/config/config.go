@@ -7,7 +7,9 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/carapace-sh/carapace/pkg/xdg"
 )
@@ -56,9 +58,18 @@ func RegisterStyle(name string, i interface{}) {
 	config.Styles[name] = i
 }
 
+// Load loads theme and styles (settings are applied on registration and reloaded by LoadSettings).
+// Styles are loaded even if the theme fails.
 func Load() error {
+	errs := make([]string, 0)
+	if err := loadTheme(); err != nil {
+		errs = append(errs, err.Error())
+	}
 	if err := load("styles", config.Styles); err != nil {
-		return err
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
 	}
 	return nil
 }
@@ -77,28 +88,184 @@ func load(name string, c configMap) error {
 		if err := json.Unmarshal(content, &unmarshalled); err != nil {
 			return err
 		}
+		apply(c, unmarshalled)
+	}
+	return nil
+}
 
-		for key, value := range unmarshalled {
-			if s, ok := c[key]; ok {
-				elem := reflect.ValueOf(s).Elem()
-				for k, v := range value {
-					if field := elem.FieldByName(k); field != (reflect.Value{}) {
-						field.SetString(v)
-					}
+func apply(c configMap, values map[string]map[string]string) {
+	for key, value := range values {
+		if s, ok := c[key]; ok {
+			elem := reflect.ValueOf(s).Elem()
+			for k, v := range value {
+				if field := elem.FieldByName(k); field != (reflect.Value{}) {
+					field.SetString(v)
 				}
 			}
 		}
 	}
+}
+
+var themes = struct {
+	sync.RWMutex
+	entries map[string]map[string]map[string]string
+}{
+	entries: make(map[string]map[string]map[string]string),
+}
+
+func RegisterTheme(name string, theme map[string]map[string]string) {
+	themes.Lock()
+	defer themes.Unlock()
+	themes.entries[name] = theme
+}
+
+// GetThemes returns the names of registered themes (including `default`).
+func GetThemes() []string {
+	themes.RLock()
+	defer themes.RUnlock()
+
+	names := []string{"default"}
+	for name := range themes.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// loadTheme applies the theme selected with `carapace.Theme`.
+func loadTheme() error {
+	name, err := GetSetting("carapace.Theme")
+	if err != nil || name == "" || name == "default" {
+		return nil
+	}
+
+	themes.RLock()
+	theme, ok := themes.entries[name]
+	themes.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown theme: '%v'", name)
+	}
+	apply(config.Styles, theme)
 	return nil
 }
 
+// MapStyles replaces all registered styles with the result of given function.
+func MapStyles(f func(s string) string) {
+	for _, i := range config.Styles {
+		elem := reflect.ValueOf(i).Elem()
+		for index := 0; index < elem.NumField(); index++ {
+			if field := elem.Field(index); field.Kind() == reflect.String && field.CanSet() {
+				field.SetString(f(field.String()))
+			}
+		}
+	}
+}
+
 func GetStyleConfigs() []string                   { return config.Styles.Keys() }
 func GetStyleFields(name string) ([]Field, error) { return config.Styles.Fields(name) }
 func SetStyle(key, value string) error {
 	return set("styles", key, strings.Replace(value, ",", " ", -1))
 }
 
+// GetStyle returns the effective value of given style (`carapace.Value`).
+func GetStyle(key string) (string, error) {
+	splitted := strings.Split(key, ".")
+	if len(splitted) != 2 {
+		return "", errors.New("invalid key")
+	}
+
+	fields, err := config.Styles.Fields(splitted[0])
+	if err != nil {
+		return "", err
+	}
+	for _, field := range fields {
+		if field.Name == splitted[1] {
+			return field.Style, nil
+		}
+	}
+	return "", fmt.Errorf("unknown style: '%v'", key)
+}
+
+// ResetStyle removes given style (`carapace.Value`), style configuration (`carapace`) or all styles (empty key) from the config file.
+func ResetStyle(key string) error {
+	return update("styles", func(c map[string]map[string]string) error {
+		switch splitted := strings.Split(key, "."); {
+		case key == "":
+			for name := range c {
+				delete(c, name)
+			}
+		case len(splitted) == 1:
+			delete(c, splitted[0])
+		case len(splitted) == 2:
+			delete(c[splitted[0]], splitted[1])
+		default:
+			return errors.New("invalid key")
+		}
+		return nil
+	})
+}
+
+// ExportStyles returns the effective styles (defaults < theme < config file) without modifying the config file.
+func ExportStyles() ([]byte, error) {
+	if err := Load(); err != nil {
+		return nil, err
+	}
+
+	exported := make(map[string]map[string]string)
+	for _, name := range GetStyleConfigs() {
+		fields, err := GetStyleFields(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			if field.Style == "" {
+				continue
+			}
+			if _, ok := exported[name]; !ok {
+				exported[name] = make(map[string]string)
+			}
+			exported[name][field.Name] = field.Style
+		}
+	}
+	return json.MarshalIndent(exported, "", "  ")
+}
+
+// ImportStyles merges given styles into the config file.
+func ImportStyles(styles map[string]map[string]string) error {
+	return update("styles", func(c map[string]map[string]string) error {
+		for name, fields := range styles {
+			if _, ok := c[name]; !ok {
+				c[name] = make(map[string]string)
+			}
+			for key, value := range fields {
+				c[name][key] = value
+			}
+		}
+		return nil
+	})
+}
+
 func set(name, key, value string) error {
+	return update(name, func(c map[string]map[string]string) error {
+		splitted := strings.Split(key, ".")
+		if len(splitted) != 2 {
+			return errors.New("invalid key")
+		}
+
+		if _, ok := c[splitted[0]]; !ok {
+			c[splitted[0]] = make(map[string]string, 0)
+		}
+		if strings.TrimSpace(value) == "" {
+			delete(c[splitted[0]], splitted[1])
+		} else {
+			c[splitted[0]][splitted[1]] = value
+		}
+		return nil
+	})
+}
+
+// update modifies the given config file.
+func update(name string, f func(c map[string]map[string]string) error) error {
 	dir, err := xdg.UserConfigDir()
 	if err != nil {
 		return err
@@ -110,33 +277,35 @@ func set(name, key, value string) error {
 		if !os.IsNotExist(err) {
 			return err
 		}
-		if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
-			return err
-		}
 		content = []byte("{}")
 	}
 
-	var config map[string]map[string]string
-	if err := json.Unmarshal(content, &config); err != nil {
+	var c map[string]map[string]string
+	if err := json.Unmarshal(content, &c); err != nil {
 		return err
 	}
+	if c == nil {
+		c = make(map[string]map[string]string)
+	}
 
-	if splitted := strings.Split(key, "."); len(splitted) != 2 {
-		return errors.New("invalid key")
-	} else {
-		if _, ok := config[splitted[0]]; !ok {
-			config[splitted[0]] = make(map[string]string, 0)
-		}
-		if strings.TrimSpace(value) == "" {
-			delete(config[splitted[0]], splitted[1])
-		} else {
-			config[splitted[0]][splitted[1]] = value
+	if err := f(c); err != nil {
+		return err
+	}
+	for key, value := range c {
+		if len(value) == 0 {
+			delete(c, key)
 		}
 	}
 
-	marshalled, err := json.MarshalIndent(config, "", "  ")
+	marshalled, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(file, marshalled, os.ModePerm)
+	if string(marshalled) == string(content) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, marshalled, 0600)
 }
@@ -0,0 +1,404 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carapace-sh/carapace/pkg/xdg"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Setting is a field of a registered settings struct.
+type Setting struct {
+	Name        string
+	Description string
+	Value       string
+	Type        string // bool, duration, int or string
+	Env         string // environment variable overriding the value
+	Values      []string
+	Tag         string
+}
+
+type setting struct {
+	i        interface{}
+	defaults reflect.Value
+}
+
+var settings = struct {
+	sync.RWMutex
+	entries map[string]setting
+}{
+	entries: make(map[string]setting),
+}
+
+// RegisterSetting registers a settings struct and applies the environment.
+// The config file is only read by LoadSettings (invalid values are ignored here and reported there).
+func RegisterSetting(name string, i interface{}) {
+	elem := reflect.ValueOf(i).Elem()
+	defaults := reflect.New(elem.Type()).Elem()
+	defaults.Set(elem)
+
+	settings.Lock()
+	settings.entries[name] = setting{i, defaults}
+	settings.Unlock()
+
+	_ = applySetting(name, nil)
+}
+
+// LoadSettings reloads the registered settings (defaults < config file < environment).
+// Invalid values are ignored and returned as error.
+func LoadSettings() error {
+	file, err := readSettings()
+	if err != nil {
+		return err
+	}
+
+	errs := make([]string, 0)
+	for _, name := range GetSettingConfigs() {
+		if err := applySetting(name, file[name]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func settingsFile() (string, error) {
+	dir, err := xdg.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v/carapace/settings.json", dir), nil
+}
+
+func readSettings() (map[string]map[string]json.RawMessage, error) {
+	file, err := settingsFile()
+	if err != nil {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var unmarshalled map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(content, &unmarshalled); err != nil {
+		return nil, fmt.Errorf("invalid settings: %v", err.Error())
+	}
+	return unmarshalled, nil
+}
+
+func applySetting(name string, values map[string]json.RawMessage) error {
+	settings.RLock()
+	s, ok := settings.entries[name]
+	settings.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown setting: '%v'", name)
+	}
+
+	elem := reflect.ValueOf(s.i).Elem()
+	elem.Set(s.defaults)
+	t := elem.Type()
+
+	errs := make([]string, 0)
+	for key, raw := range values {
+		sf, ok := t.FieldByName(key)
+		if !ok || sf.PkgPath != "" {
+			errs = append(errs, fmt.Sprintf("unknown setting: '%v.%v'", name, key))
+			continue
+		}
+
+		validated := reflect.New(sf.Type).Elem()
+		if err := setJSON(validated, raw); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid value for '%v.%v': %v", name, key, err.Error()))
+			continue
+		}
+		if err := validate(sf, validated); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid value for '%v.%v': %v", name, key, err.Error()))
+			continue
+		}
+		elem.FieldByIndex(sf.Index).Set(validated)
+	}
+
+	for index := 0; index < t.NumField(); index++ {
+		sf := t.Field(index)
+		env := sf.Tag.Get("env")
+		if env == "" {
+			continue
+		}
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+
+		validated := reflect.New(sf.Type).Elem()
+		switch {
+		case settingType(validated) == "bool":
+			validated.SetBool(true) // any non-empty value enables boolean environment variables
+		default:
+			if err := setString(validated, value); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid value for '%v': %v", env, err.Error()))
+				continue
+			}
+			if err := validate(sf, validated); err != nil {
+				legacy := sf.Tag.Get("legacy")
+				if legacy == "" {
+					errs = append(errs, fmt.Sprintf("invalid value for '%v': %v", env, err.Error()))
+					continue
+				}
+				validated.SetString(legacy) // environment variable was previously a boolean (e.g. `CARAPACE_LOG=1`)
+			}
+		}
+		elem.Field(index).Set(validated)
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validate checks given value against the `values` tag of the field.
+func validate(sf reflect.StructField, v reflect.Value) error {
+	tag := sf.Tag.Get("values")
+	if tag == "" {
+		return nil
+	}
+
+	value := formatValue(v)
+	for _, allowed := range strings.Split(tag, ",") {
+		if value == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected one of [%v] [was: %v]", strings.Replace(tag, ",", ", ", -1), value)
+}
+
+func settingType(v reflect.Value) string {
+	switch {
+	case v.Type() == durationType:
+		return "duration"
+	case v.Kind() == reflect.Bool:
+		return "bool"
+	case v.Kind() == reflect.Int:
+		return "int"
+	case v.Kind() == reflect.String:
+		return "string"
+	default:
+		return v.Type().String()
+	}
+}
+
+// setString sets the field from its string representation (`true`, `5s`, `10`).
+func setString(v reflect.Value, s string) error {
+	switch settingType(v) {
+	case "bool":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("expected bool [was: %v]", s)
+		}
+		v.SetBool(b)
+	case "duration":
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("expected duration [was: %v]", s)
+		}
+		v.SetInt(int64(d))
+	case "int":
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("expected int [was: %v]", s)
+		}
+		v.SetInt(int64(i))
+	case "string":
+		v.SetString(s)
+	default:
+		return fmt.Errorf("unsupported type: %v", v.Type())
+	}
+	return nil
+}
+
+func setJSON(v reflect.Value, raw json.RawMessage) error {
+	if settingType(v) == "duration" {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("expected duration [was: %v]", string(raw))
+		}
+		return setString(v, s)
+	}
+	return json.Unmarshal(raw, v.Addr().Interface())
+}
+
+func formatValue(v reflect.Value) string {
+	if settingType(v) == "duration" {
+		return time.Duration(v.Int()).String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func GetSettingConfigs() []string {
+	settings.RLock()
+	defer settings.RUnlock()
+
+	names := make([]string, 0, len(settings.entries))
+	for name := range settings.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func GetSettingFields(name string) ([]Setting, error) {
+	settings.RLock()
+	s, ok := settings.entries[name]
+	settings.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown setting: '%v'", name)
+	}
+
+	fields := make([]Setting, 0)
+	t := reflect.TypeOf(s.i).Elem()
+	v := reflect.ValueOf(s.i).Elem()
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		setting := Setting{
+			Name:        field.Name,
+			Description: field.Tag.Get("description"),
+			Value:       formatValue(v.Field(index)),
+			Type:        settingType(v.Field(index)),
+			Env:         field.Tag.Get("env"),
+			Tag:         field.Tag.Get("tag"),
+		}
+		if values := field.Tag.Get("values"); values != "" {
+			setting.Values = strings.Split(values, ",")
+		}
+		fields = append(fields, setting)
+	}
+	return fields, nil
+}
+
+// GetSettingField returns given setting (`carapace.Hidden`).
+func GetSettingField(key string) (*Setting, error) {
+	splitted := strings.SplitN(key, ".", 2)
+	if len(splitted) != 2 {
+		return nil, errors.New("invalid key")
+	}
+
+	fields, err := GetSettingFields(splitted[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if field.Name == splitted[1] {
+			return &field, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown setting: '%v'", key)
+}
+
+// GetSetting returns the current value of given setting (`carapace.Hidden`).
+func GetSetting(key string) (string, error) {
+	field, err := GetSettingField(key)
+	if err != nil {
+		return "", err
+	}
+	return field.Value, nil
+}
+
+// SetSetting validates and persists given setting. An empty value removes it from the config file.
+func SetSetting(key, value string) error {
+	splitted := strings.SplitN(key, ".", 2)
+	if len(splitted) != 2 {
+		return errors.New("invalid key")
+	}
+	name, fieldName := splitted[0], splitted[1]
+
+	settings.RLock()
+	s, ok := settings.entries[name]
+	settings.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown setting: '%v'", name)
+	}
+
+	sf, ok := reflect.TypeOf(s.i).Elem().FieldByName(fieldName)
+	if !ok || sf.PkgPath != "" {
+		return fmt.Errorf("unknown setting: '%v'", key)
+	}
+
+	var raw json.RawMessage
+	if strings.TrimSpace(value) != "" {
+		validated := reflect.New(sf.Type).Elem()
+		if err := setString(validated, value); err != nil {
+			return err
+		}
+		if err := validate(sf, validated); err != nil {
+			return err
+		}
+
+		var err error
+		if settingType(validated) == "duration" {
+			raw, err = json.Marshal(formatValue(validated))
+		} else {
+			raw, err = json.Marshal(validated.Interface())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := settingsFile()
+	if err != nil {
+		return err
+	}
+
+	content, err := readSettings()
+	if err != nil {
+		return err
+	}
+	if content == nil {
+		content = make(map[string]map[string]json.RawMessage)
+	}
+	if _, ok := content[name]; !ok {
+		content[name] = make(map[string]json.RawMessage)
+	}
+
+	if raw == nil {
+		delete(content[name], fieldName)
+	} else {
+		content[name][fieldName] = raw
+	}
+
+	marshalled, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, marshalled, 0600); err != nil {
+		return err
+	}
+	_ = applySetting(name, content[name]) // invalid values of other fields are reported by LoadSettings
+	return nil
+}
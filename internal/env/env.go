@@ -4,29 +4,55 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/carapace-sh/carapace/internal/common"
 )
 
 const (
+	CARAPACE_CACHE_BUDGET  = "CARAPACE_CACHE_BUDGET"  // maximum size of the cache (see settings.Carapace)
+	CARAPACE_COLOR_DEPTH   = "CARAPACE_COLOR_DEPTH"   // color depth of the terminal (see settings.Carapace)
 	CARAPACE_COVERDIR      = "CARAPACE_COVERDIR"      // coverage directory for sandbox tests
-	CARAPACE_HIDDEN        = "CARAPACE_HIDDEN"        // show hidden commands/flags
-	CARAPACE_LENIENT       = "CARAPACE_LENIENT"       // allow unknown flags
-	CARAPACE_LOG           = "CARAPACE_LOG"           // enable logging
-	CARAPACE_MATCH         = "CARAPACE_MATCH"         // match case insensitive
+	CARAPACE_DESCRIPTION   = "CARAPACE_DESCRIPTION"   // maximum width of descriptions (see settings.Carapace)
+	CARAPACE_HIDDEN        = "CARAPACE_HIDDEN"        // show hidden commands/flags (see settings.Carapace)
+	CARAPACE_LENIENT       = "CARAPACE_LENIENT"       // allow unknown flags (see settings.Carapace)
+	CARAPACE_LOG           = "CARAPACE_LOG"           // log level (see settings.Carapace)
+	CARAPACE_LOG_FORMAT    = "CARAPACE_LOG_FORMAT"    // log format (see settings.Carapace)
+	CARAPACE_LOG_SIZE      = "CARAPACE_LOG_SIZE"      // maximum size of the log file (see settings.Carapace)
+	CARAPACE_MATCH         = "CARAPACE_MATCH"         // match case insensitive (see settings.Carapace)
 	CARAPACE_SANDBOX       = "CARAPACE_SANDBOX"       // mock context for sandbox tests
+	CARAPACE_SHELL         = "CARAPACE_SHELL"         // override shell detection
+	CARAPACE_THEME         = "CARAPACE_THEME"         // style theme (see settings.Carapace)
+	CARAPACE_TIMEOUT       = "CARAPACE_TIMEOUT"       // maximum duration of a completion (see settings.Carapace)
+	CARAPACE_ZSH_COMPDUMP  = "CARAPACE_ZSH_COMPDUMP"  // zsh compdump file used by ActionBridgeZsh
 	CARAPACE_ZSH_HASH_DIRS = "CARAPACE_ZSH_HASH_DIRS" // zsh hash directories
 	CLICOLOR               = "CLICOLOR"               // disable color
+	COLORTERM              = "COLORTERM"              // color depth of the terminal (truecolor)
+	COLUMNS                = "COLUMNS"                // terminal width
+	EZA_COLORS             = "EZA_COLORS"             // file colors of eza (extends LS_COLORS)
+	LS_COLORS              = "LS_COLORS"              // file colors
 	NO_COLOR               = "NO_COLOR"               // disable color
+	TERM                   = "TERM"                   // terminal type (e.g. xterm-256color)
 )
 
 func ColorDisabled() bool {
 	return os.Getenv(NO_COLOR) != "" || os.Getenv(CLICOLOR) == "0"
 }
 
-func Lenient() bool {
-	return os.Getenv(CARAPACE_LENIENT) != ""
+func Columns() int {
+	return positiveInt(os.Getenv(COLUMNS))
+}
+
+func positiveInt(s string) int {
+	if i, err := strconv.Atoi(s); err == nil && i > 0 {
+		return i
+	}
+	return 0
+}
+
+func Shell() string {
+	return os.Getenv(CARAPACE_SHELL)
 }
 
 func Hashdirs() string {
@@ -43,20 +69,8 @@ func Sandbox() (m *common.Mock, err error) {
 	return
 }
 
-func Log() bool {
-	return os.Getenv(CARAPACE_LOG) != ""
-}
-
-func Hidden() bool {
-	return os.Getenv(CARAPACE_HIDDEN) != ""
-}
-
 func CoverDir() string {
 	return os.Getenv(CARAPACE_COVERDIR) // custom env for GOCOVERDIR so that it works together with `-coverprofile`
 }
 
 func isGoRun() bool { return strings.HasPrefix(os.Args[0], os.TempDir()+"/go-build") }
-
-func Match() string { // see match.Match
-	return os.Getenv(CARAPACE_MATCH)
-}
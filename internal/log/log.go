@@ -1,32 +1,113 @@
+// Package log provides structured logging to `${TMPDIR}/carapace/<executable>.log`
 package log
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
 
-	"github.com/carapace-sh/carapace/internal/env"
 	"github.com/carapace-sh/carapace/internal/uid"
 	"github.com/carapace-sh/carapace/pkg/ps"
+	"github.com/carapace-sh/carapace/pkg/settings"
 )
 
-var LOG = log.New(ioutil.Discard, "", log.Flags())
+var (
+	// Logger is the structured logger (discards everything unless enabled by `settings.Carapace.Log`).
+	Logger = slog.New(discardHandler{})
+	// LOG is a plain logger writing to the current Logger with level debug.
+	LOG = slog.NewLogLogger(currentHandler{}, slog.LevelDebug)
+	// TraceID identifies the entries of the current invocation.
+	TraceID = traceID()
+)
+
+var writer *rotatingWriter
 
 func init() {
-	if !env.Log() {
+	Configure()
+}
+
+// Configure (re)creates Logger from the current settings (e.g. after the config file was loaded).
+func Configure() {
+	if writer != nil {
+		writer.Close()
+		writer = nil
+	}
+	Logger = slog.New(discardHandler{})
+
+	level, ok := parseLevel(settings.Carapace.Log)
+	if !ok {
 		return
 	}
 
 	tmpdir := fmt.Sprintf("%v/carapace", os.TempDir())
-	if err := os.MkdirAll(tmpdir, os.ModePerm); err != nil {
+	if err := os.MkdirAll(tmpdir, 0o700); err != nil {
 		log.Fatal(err.Error())
 	}
 
 	file := fmt.Sprintf("%v/%v.log", tmpdir, uid.Executable())
-	if logfileWriter, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o666); err != nil {
+	var err error
+	if writer, err = newRotatingWriter(file, int64(settings.Carapace.LogSize)*1024*1024); err != nil {
 		log.Fatal(err.Error())
-	} else {
-		LOG = log.New(logfileWriter, ps.DetermineShell()+" ", log.Flags()|log.Lmsgprefix|log.Lmicroseconds)
 	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(writer, options)
+	if settings.Carapace.LogFormat == "json" {
+		handler = slog.NewJSONHandler(writer, options)
+	}
+
+	shell, strategy := ps.DetermineShellStrategy()
+	Logger = slog.New(handler).With("trace", TraceID, "shell", shell)
+	Logger.Debug("determined shell", "strategy", strategy)
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// currentHandler forwards to the handler of Logger at the time of logging.
+type currentHandler struct{}
+
+func (currentHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return Logger.Handler().Enabled(ctx, level)
+}
+
+func (currentHandler) Handle(ctx context.Context, r slog.Record) error {
+	return Logger.Handler().Handle(ctx, r)
+}
+
+func (currentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return Logger.Handler().WithAttrs(attrs)
+}
+
+func (currentHandler) WithGroup(name string) slog.Handler {
+	return Logger.Handler().WithGroup(name)
+}
+
+// parseLevel parses given log level (`1` and `true` are kept for compatibility as `debug`).
+func parseLevel(s string) (slog.Level, bool) {
+	switch strings.ToLower(s) {
+	case "debug", "1", "true":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	default:
+		return 0, false
+	}
+}
+
+func traceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", os.Getpid())
+	}
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	_test := func(s string, expected slog.Level, enabled bool) {
+		if level, ok := parseLevel(s); ok != enabled || (ok && level != expected) {
+			t.Errorf("expected %v/%v for '%v' [was: %v/%v]", expected, enabled, s, level, ok)
+		}
+	}
+
+	_test("", 0, false)
+	_test("off", 0, false)
+	_test("debug", slog.LevelDebug, true)
+	_test("DEBUG", slog.LevelDebug, true)
+	_test("1", slog.LevelDebug, true)
+	_test("true", slog.LevelDebug, true)
+	_test("info", slog.LevelInfo, true)
+}
+
+func TestRotatingWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(file, []byte("existing\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := newRotatingWriter(file, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { w.file.Close() }()
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if content, err := os.ReadFile(file + ".1"); err != nil || string(content) != "existing\nfirst\n" {
+		t.Errorf("unexpected rotated content: %#v (%v)", string(content), err)
+	}
+	if content, err := os.ReadFile(file); err != nil || string(content) != "second\nthird\n" {
+		t.Errorf("unexpected content: %#v (%v)", string(content), err)
+	}
+
+	for _, path := range []string{file, file + ".1"} {
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("expected mode 0600 for %v [was: %v]", path, info.Mode().Perm())
+		}
+	}
+
+}
+
+func TestTraceID(t *testing.T) {
+	if a, b := traceID(), traceID(); len(a) != 16 || strings.Trim(a, "0123456789abcdef") != "" || a == b {
+		t.Errorf("unexpected trace ids: %v, %v", a, b)
+	}
+}
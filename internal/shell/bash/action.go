@@ -78,7 +78,7 @@ func ActionRawValues(currentWord string, meta common.Meta, values common.RawValu
 	vals := make([]string, len(values))
 	for index, val := range values {
 		if len(values) == 1 || compType != COMP_TYPE_LIST_SUCCESSIVE_TABS {
-			nospace = nospace || meta.Nospace.Matches(val.Value)
+			nospace = nospace || val.IsNospace(meta.Nospace)
 
 			vals[index] = sanitizer.Replace(val.Value)
 			if requiresQuoting(vals[index]) {
@@ -5,17 +5,38 @@ import (
 	"strings"
 
 	"github.com/carapace-sh/carapace/internal/common"
+	"github.com/carapace-sh/carapace/pkg/style"
 )
 
+var sanitizer = strings.NewReplacer(
+	"\n", ``,
+	"\r", ``,
+	"\t", ``,
+)
+
+// messageIndicator marks lines which are printed by the snippet instead of being added as candidates.
+const messageIndicator = "\001"
+
 // ActionRawValues formats values for bash_ble.
 func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
-	vals := make([]string, len(values))
-	for index, val := range values {
+	vals := make([]string, 0, len(values))
+	for _, msg := range meta.Messages.List() {
+		vals = append(vals, formatMessage(msg.Message, msg.Severity.Style()))
+	}
+	if len(values) == 0 && meta.Usage != "" {
+		vals = append(vals, formatMessage(meta.Usage, style.Carapace.Usage))
+	}
+
+	for _, val := range values {
 		suffix := " "
-		if meta.Nospace.Matches(val.Value) {
+		if val.IsNospace(meta.Nospace) {
 			suffix = ""
 		}
-		vals[index] = fmt.Sprintf("%v\t%v\x1c%v\x1c%v\x1c%v", val.Value, val.Display, "", suffix, val.TrimmedDescription())
+		vals = append(vals, fmt.Sprintf("%v\t%v\x1c%v\x1c%v\x1c%v", val.Value, val.Display, "", suffix, val.TrimmedDescription()))
 	}
 	return strings.Join(vals, "\n")
 }
+
+func formatMessage(msg, _style string) string {
+	return fmt.Sprintf("%v\x1b[%vm%v\x1b[%vm", messageIndicator, style.SGR(_style), sanitizer.Replace(msg), style.SGR("fg-default"))
+}
@@ -31,7 +31,11 @@ _%v_completion_ble() {
 
     local cand
     for cand in "${c[@]}"; do
-      [ ! -z "$cand" ] && ble/complete/cand/yield mandb "${cand%%$'\t'*}" "${cand##*$'\t'}"
+      if [[ $cand == $'\001'* ]]; then
+        ble/util/print "${cand#$'\001'}" >&2
+      elif [ ! -z "$cand" ]; then
+        ble/complete/cand/yield mandb "${cand%%$'\t'*}" "${cand##*$'\t'}"
+      fi
     done
   else
     complete -F _%v_completion %v
@@ -19,18 +19,24 @@ func sanitize(values []common.RawValue) []common.RawValue {
 	for index, v := range values {
 		(&values[index]).Value = sanitizer.Replace(v.Value)
 		(&values[index]).Display = sanitizer.Replace(v.Display)
-		(&values[index]).Description = sanitizer.Replace(v.TrimmedDescription())
+		(&values[index]).Description = v.MultilineDescription()
 	}
 	return values
 }
 
 type completion struct {
 	Usage            string
-	Messages         common.Messages
+	Messages         []message
 	DescriptionStyle string
 	Candidates       []complexCandidate
 }
 
+type message struct {
+	Message  string
+	Severity string
+	Style    string
+}
+
 type complexCandidate struct {
 	Value       string
 	Display     string
@@ -54,7 +60,7 @@ func ActionRawValues(currentWord string, meta common.Meta, values common.RawValu
 	vals := make([]complexCandidate, len(values))
 	for index, val := range sanitize(values) {
 		suffix := " "
-		if meta.Nospace.Matches(val.Value) {
+		if val.IsNospace(meta.Nospace) {
 			suffix = ""
 		}
 
@@ -68,9 +74,14 @@ func ActionRawValues(currentWord string, meta common.Meta, values common.RawValu
 		meta.Usage = "" // TODO edit:notify is persistent, so avoid spamming the user for now
 	}
 
+	messages := make([]message, 0)
+	for _, m := range meta.Messages.List() {
+		messages = append(messages, message{Message: m.Message, Severity: m.Severity.String(), Style: m.Severity.Style()})
+	}
+
 	m, _ := json.Marshal(completion{
 		Usage:            meta.Usage,
-		Messages:         meta.Messages,
+		Messages:         messages,
 		DescriptionStyle: descriptionStyle,
 		Candidates:       vals,
 	})
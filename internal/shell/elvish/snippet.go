@@ -13,7 +13,7 @@ func Snippet(cmd *cobra.Command) string {
 	return fmt.Sprintf(`set edit:completion:arg-completer[%v] = {|@arg|
     %v _carapace elvish (all $arg) | from-json | each {|completion|
 		put $completion[Messages] | all (one) | each {|m|
-			edit:notify (styled "error: " red)$m
+			edit:notify (styled $m[Severity]": " $m[Style])$m[Message]
 		}
 		if (not-eq $completion[Usage] "") {
 			edit:notify (styled "usage: " $completion[DescriptionStyle])$completion[Usage]
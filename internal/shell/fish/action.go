@@ -2,8 +2,9 @@ package fish
 
 import (
 	"fmt"
-	"github.com/carapace-sh/carapace/internal/common"
 	"strings"
+
+	"github.com/carapace-sh/carapace/internal/common"
 )
 
 var sanitizer = strings.NewReplacer(
@@ -14,9 +15,10 @@ var sanitizer = strings.NewReplacer(
 
 // ActionRawValues formats values for fish.
 func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
-	vals := make([]string, len(values))
-	for index, val := range values {
-		vals[index] = fmt.Sprintf("%v\t%v", sanitizer.Replace(val.Value), sanitizer.Replace(val.TrimmedDescription()))
+	vals := make([]string, 0, len(values))
+	vals = append(vals, message{meta, len(values) == 0}.Format()...)
+	for _, val := range values {
+		vals = append(vals, fmt.Sprintf("%v\t%v", sanitizer.Replace(val.Value), sanitizer.Replace(val.TrimmedDescription())))
 	}
 	return strings.Join(vals, "\n")
 }
@@ -0,0 +1,32 @@
+package fish
+
+import (
+	"fmt"
+
+	"github.com/carapace-sh/carapace/internal/common"
+	"github.com/carapace-sh/carapace/pkg/style"
+)
+
+// messageIndicator marks lines which are printed by the snippet instead of being added as candidates.
+const messageIndicator = "\001"
+
+type message struct {
+	common.Meta
+	showUsage bool
+}
+
+// Format returns messages (and usage if no values are shown) as marked lines.
+func (m message) Format() []string {
+	formatted := make([]string, 0)
+	for _, msg := range m.Messages.List() {
+		formatted = append(formatted, m.formatMessage(msg.Message, msg.Severity.Style()))
+	}
+	if m.showUsage && m.Usage != "" {
+		formatted = append(formatted, m.formatMessage(m.Usage, style.Carapace.Usage))
+	}
+	return formatted
+}
+
+func (m message) formatMessage(msg, _style string) string {
+	return fmt.Sprintf("%v\x1b[%vm%v\x1b[%vm", messageIndicator, style.SGR(_style), sanitizer.Replace(msg), style.SGR("fg-default"))
+}
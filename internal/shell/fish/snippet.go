@@ -23,7 +23,13 @@ func Snippet(cmd *cobra.Command) string {
 end
 
 function _%v_callback
-  commandline -cp | sed "s/\$/"(_%v_quote_suffix)"/" | sed "s/ \$/ ''/" | xargs %v _carapace fish
+  set -l lines (commandline -cp | sed "s/\$/"(_%v_quote_suffix)"/" | sed "s/ \$/ ''/" | xargs %v _carapace fish)
+  set -l messages (string replace -r -f -- '^\x01' '' $lines)
+  if set -q messages[1]
+    printf '\n%%s' $messages >&2
+    commandline -f repaint
+  end
+  string match -v -r -- '^\x01' $lines
 end
 
 complete -c %v -f
@@ -31,7 +31,7 @@ type suggestion struct {
 func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
 	vals := make([]suggestion, len(values))
 	for index, val := range sanitize(values) {
-		if !meta.Nospace.Matches(val.Value) {
+		if !val.IsNospace(meta.Nospace) {
 			val.Value = val.Value + " "
 		}
 
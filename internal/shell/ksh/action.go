@@ -0,0 +1,91 @@
+package ksh
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/common"
+)
+
+var sanitizer = strings.NewReplacer(
+	"\n", ``,
+	"\r", ``,
+	"\t", ``,
+)
+
+var quoter = strings.NewReplacer(
+	`\`, `\\`,
+	`&`, `\&`,
+	`<`, `\<`,
+	`>`, `\>`,
+	"`", "\\`",
+	`'`, `\'`,
+	`"`, `\"`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`|`, `\|`,
+	`?`, `\?`,
+	`(`, `\(`,
+	`)`, `\)`,
+	`;`, `\;`,
+	` `, `\ `,
+	`[`, `\[`,
+	`]`, `\]`,
+	`*`, `\*`,
+	`~`, `\~`,
+)
+
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[0:i]
+}
+
+func commonValuePrefix(values ...common.RawValue) (prefix string) {
+	for index, val := range values {
+		if index == 0 {
+			prefix = val.Value
+		} else {
+			prefix = commonPrefix(prefix, val.Value)
+		}
+	}
+	return
+}
+
+// ActionRawValues formats values for ksh.
+//
+// The first line contains the text to insert after the current word,
+// followed by the candidates to list if there are more than one.
+func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
+	insert := ""
+	switch len(values) {
+	case 0:
+	case 1:
+		if strings.HasPrefix(values[0].Value, currentWord) {
+			insert = quoter.Replace(sanitizer.Replace(strings.TrimPrefix(values[0].Value, currentWord)))
+			if !values[0].IsNospace(meta.Nospace) {
+				insert = insert + " "
+			}
+		}
+	default:
+		if prefix := commonValuePrefix(values...); strings.HasPrefix(prefix, currentWord) {
+			insert = quoter.Replace(sanitizer.Replace(strings.TrimPrefix(prefix, currentWord)))
+		}
+	}
+
+	lines := []string{insert}
+	if len(values) > 1 {
+		for _, val := range values {
+			if val.Description != "" {
+				lines = append(lines, fmt.Sprintf("%v (%v)", sanitizer.Replace(val.Display), sanitizer.Replace(val.TrimmedDescription())))
+			} else {
+				lines = append(lines, sanitizer.Replace(val.Display))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,89 @@
+// Package ksh provides Korn shell completion
+package ksh
+
+import (
+	"fmt"
+
+	"github.com/carapace-sh/carapace/internal/uid"
+	"github.com/spf13/cobra"
+)
+
+// Snippet creates the ksh completion script.
+//
+// Neither ksh93 nor mksh have programmable completion.
+// In ksh93 the tab key is intercepted with a KEYBD trap which replaces it with the text to insert
+// using the `.sh.edchar` discipline variable.
+// In mksh the tab key is bound to a macro which comments out the current line, invokes the completer
+// (reading the line from history) and recalls the completed line added to history with `print -s`.
+func Snippet(cmd *cobra.Command) string {
+	return fmt.Sprintf(`case " ${_carapace_completers} " in
+*" %[1]v "*) ;;
+*) _carapace_completers="${_carapace_completers} %[1]v" ;;
+esac
+
+function _%[1]v_completion {
+  typeset line="$1"
+  if echo "${line}''" | xargs echo 2>/dev/null > /dev/null; then
+    echo "${line}''" | xargs %[2]v _carapace ksh 2>/dev/null
+  elif echo "${line}" | sed "s/\$/'/" | xargs echo 2>/dev/null > /dev/null; then
+    echo "${line}" | sed "s/\$/'/" | xargs %[2]v _carapace ksh 2>/dev/null
+  else
+    echo "${line}" | sed 's/$/"/' | xargs %[2]v _carapace ksh 2>/dev/null
+  fi
+}
+
+if ! typeset -f _carapace_completer > /dev/null; then
+  # prints the completion function for the command of given line
+  function _carapace_completer {
+    typeset name="${1##+([	 ])}" # first word without word splitting (which would expand globs)
+    name="${name%%%%[	 ]*}"
+    name="${name##*/}"
+    case " ${_carapace_completers} " in
+    *" ${name} "*) print -r -- "_${name}_completion" ;;
+    esac
+  }
+
+  case "${KSH_VERSION}" in
+  *MIRBSD*|*PD\ KSH*|*LEGACY\ KSH*)
+    function _carapace_mksh {
+      typeset nl='
+'
+      typeset line="$(fc -ln -2 -2)" # commented line preceding this invocation
+      line="${line##+([	 ])}"
+      line="${line#\#}"
+
+      typeset completer="$(_carapace_completer "${line}")" output="" insert=""
+      if [[ -n "${completer}" && "${line}" == *" "* ]]; then
+        output="$("${completer}" "${line}")"
+        insert="${output%%%%${nl}*}"
+        if [[ "${output}" == *"${nl}"* ]]; then
+          print -r -u2 -- "${output#*${nl}}"
+        fi
+      fi
+      print -s -- "${line}${insert}"
+    }
+    bind -m '^I'='^A#^M_carapace_mksh^M^P'
+    ;;
+  *)
+    function _carapace_keybd {
+      [[ "${.sh.edchar}" == $'\t' ]] || return
+      typeset line="${.sh.edtext:0:${.sh.edcol}}"
+      [[ "${line}" == *[[:space:]]* ]] || return # command name itself
+
+      typeset completer="$(_carapace_completer "${.sh.edtext}")"
+      [[ -n "${completer}" ]] || return
+
+      typeset output="$("${completer}" "${line}")"
+      typeset insert="${output%%%%$'\n'*}"
+      if [[ "${output}" == *$'\n'* ]]; then
+        print -r -u2 -- $'\n'"${output#*$'\n'}"
+        insert="${insert}"$'\cL' # redraw line after listing the candidates
+      fi
+      .sh.edchar="${insert}"
+    }
+    trap _carapace_keybd KEYBD
+    ;;
+  esac
+fi
+`, cmd.Name(), uid.Executable())
+}
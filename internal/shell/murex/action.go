@@ -0,0 +1,81 @@
+package murex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/common"
+)
+
+var sanitizer = strings.NewReplacer(
+	"\n", ``,
+	"\r", ``,
+	"\t", ``,
+)
+
+var quoter = strings.NewReplacer(
+	`\`, `\\`,
+	` `, `\ `,
+	`'`, `\'`,
+	`"`, `\"`,
+	`$`, `\$`,
+	`@`, `\@`,
+	`(`, `\(`,
+	`)`, `\)`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`;`, `\;`,
+	`|`, `\|`,
+	`&`, `\&`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`#`, `\#`,
+)
+
+// ActionRawValues formats values for murex.
+// The JSON object is written in the order of given values since `encoding/json` would sort the keys.
+func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
+	tags := 0
+	values.EachTag(func(tag string, values common.RawValues) { tags++ })
+
+	keys := make([]string, 0, len(values))
+	descriptions := make(map[string][]string, len(values))
+	for _, val := range values {
+		value := quoter.Replace(sanitizer.Replace(val.Value))
+		if !val.IsNospace(meta.Nospace) {
+			value = value + " "
+		}
+
+		description := sanitizer.Replace(val.TrimmedDescription())
+		if tags > 1 && val.Tag != "" { // murex has no groups so only show tags when they help to distinguish values
+			description = strings.TrimSpace(fmt.Sprintf("%v (%v)", description, val.Tag))
+		}
+
+		if _, ok := descriptions[value]; !ok {
+			keys = append(keys, value)
+			descriptions[value] = nil
+		}
+		if description != "" {
+			descriptions[value] = append(descriptions[value], description) // keep the descriptions of duplicate values
+		}
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString("{")
+	for index, key := range keys {
+		if index > 0 {
+			buffer.WriteString(",")
+		}
+		k, _ := json.Marshal(key)
+		v, _ := json.Marshal(strings.Join(descriptions[key], ", "))
+		buffer.Write(k)
+		buffer.WriteString(":")
+		buffer.Write(v)
+	}
+	buffer.WriteString("}")
+	return buffer.String()
+}
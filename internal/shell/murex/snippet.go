@@ -0,0 +1,20 @@
+// Package murex provides Murex completion
+package murex
+
+import (
+	"fmt"
+
+	"github.com/carapace-sh/carapace/internal/uid"
+	"github.com/spf13/cobra"
+)
+
+// Snippet creates the murex completion script.
+func Snippet(cmd *cobra.Command) string {
+	return fmt.Sprintf(`autocomplete set %v %%[{
+    DynamicDesc: '{
+        %v _carapace murex @ARGS
+    }'
+    ListView: true
+}]
+`, cmd.Name(), uid.Executable())
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/carapace-sh/carapace/internal/common"
+	"github.com/carapace-sh/carapace/pkg/style"
 )
 
 type record struct {
@@ -29,7 +30,7 @@ func sanitize(values []common.RawValue) []common.RawValue {
 	for index, v := range values {
 		(&values[index]).Value = sanitizer.Replace(v.Value)
 		(&values[index]).Display = sanitizer.Replace(v.Display)
-		(&values[index]).Description = sanitizer.Replace(v.Description)
+		(&values[index]).Description = v.MultilineDescription()
 	}
 	return values
 }
@@ -38,7 +39,7 @@ func sanitize(values []common.RawValue) []common.RawValue {
 func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
 	vals := make([]record, len(values))
 	for index, val := range sanitize(values) {
-		nospace := meta.Nospace.Matches(val.Value)
+		nospace := val.IsNospace(meta.Nospace)
 		if strings.ContainsAny(val.Value, ` {}()[]<>$&"'|;#\`+"`") {
 			switch {
 			case strings.HasPrefix(val.Value, "~"):
@@ -55,10 +56,27 @@ func ActionRawValues(currentWord string, meta common.Meta, values common.RawValu
 		vals[index] = record{
 			Value:       val.Value,
 			Display:     val.Display,
-			Description: val.TrimmedDescription(),
+			Description: val.Description,
 			Style:       convertStyle(val.Style),
 		}
 	}
+
+	for _, msg := range meta.Messages.List() {
+		vals = append(vals, messageRecord(currentWord, msg.Message, msg.Severity.Style()))
+	}
+	if len(values) == 0 && meta.Usage != "" {
+		vals = append(vals, messageRecord(currentWord, meta.Usage, style.Carapace.Usage))
+	}
 	m, _ := json.Marshal(vals)
 	return string(m)
 }
+
+// messageRecord creates a description-only record which inserts the current word unchanged.
+func messageRecord(currentWord, description, _style string) record {
+	return record{
+		Value:       currentWord,
+		Display:     "",
+		Description: sanitizer.Replace(description),
+		Style:       convertStyle(_style),
+	}
+}
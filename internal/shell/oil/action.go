@@ -18,7 +18,7 @@ const nospaceIndicator = "\001"
 func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
 	vals := make([]string, len(values))
 	for index, val := range values {
-		if meta.Nospace.Matches(val.Value) {
+		if val.IsNospace(meta.Nospace) {
 			val.Value = val.Value + nospaceIndicator
 		}
 
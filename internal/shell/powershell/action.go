@@ -41,11 +41,13 @@ func ActionRawValues(currentWord string, meta common.Meta, values common.RawValu
 		descriptionStyle = s
 	}
 
+	toolTip := message{meta}.Format()
+
 	vals := make([]completionResult, 0, len(values))
 	for _, val := range values {
 		if val.Value != "" { // must not be empty - any empty `''` parameter in CompletionResult causes an error
 			val.Value = sanitizer.Replace(val.Value)
-			nospace := meta.Nospace.Matches(val.Value)
+			nospace := val.IsNospace(meta.Nospace)
 
 			if strings.ContainsAny(val.Value, ` {}()[]*$?\"|<>&(),;#`+"`") {
 				val.Value = fmt.Sprintf("'%v'", val.Value)
@@ -68,10 +70,20 @@ func ActionRawValues(currentWord string, meta common.Meta, values common.RawValu
 			vals = append(vals, completionResult{
 				CompletionText: val.Value,
 				ListItemText:   ensureNotEmpty(listItemText),
-				ToolTip:        ensureNotEmpty(" "),
+				ToolTip:        ensureNotEmpty(strings.TrimSpace(val.MultilineDescription() + "\n" + toolTip)),
 			})
 		}
 	}
+
+	if len(vals) == 0 && toolTip != "" {
+		// show messages and usage with a candidate that inserts the current word unchanged
+		listItemText := fmt.Sprintf("`e[%vm%v`e[0m", sgr(message{meta}.style()), sanitizer.Replace(strings.SplitN(toolTip, "\n", 2)[0]))
+		vals = append(vals, completionResult{
+			CompletionText: ensureNotEmpty(currentWord),
+			ListItemText:   listItemText,
+			ToolTip:        toolTip,
+		})
+	}
 	m, _ := json.Marshal(vals)
 	return string(m)
 }
@@ -0,0 +1,31 @@
+package powershell
+
+import (
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/common"
+	"github.com/carapace-sh/carapace/pkg/style"
+)
+
+type message struct {
+	common.Meta
+}
+
+// Format returns messages and usage as tooltip.
+func (m message) Format() string {
+	formatted := make([]string, 0)
+	for _, msg := range m.Messages.List() {
+		formatted = append(formatted, msg.Severity.String()+": "+msg.Message)
+	}
+	if m.Usage != "" {
+		formatted = append(formatted, "usage: "+m.Usage)
+	}
+	return strings.Join(formatted, "\n")
+}
+
+func (m message) style() string {
+	if messages := m.Messages.List(); len(messages) > 0 {
+		return messages[0].Severity.Style()
+	}
+	return style.Carapace.Usage
+}
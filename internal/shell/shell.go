@@ -13,13 +13,17 @@ import (
 	"github.com/carapace-sh/carapace/internal/shell/export"
 	"github.com/carapace-sh/carapace/internal/shell/fish"
 	"github.com/carapace-sh/carapace/internal/shell/ion"
+	"github.com/carapace-sh/carapace/internal/shell/ksh"
+	"github.com/carapace-sh/carapace/internal/shell/murex"
 	"github.com/carapace-sh/carapace/internal/shell/nushell"
 	"github.com/carapace-sh/carapace/internal/shell/oil"
 	"github.com/carapace-sh/carapace/internal/shell/powershell"
 	"github.com/carapace-sh/carapace/internal/shell/tcsh"
 	"github.com/carapace-sh/carapace/internal/shell/xonsh"
+	"github.com/carapace-sh/carapace/internal/shell/yash"
 	"github.com/carapace-sh/carapace/internal/shell/zsh"
 	"github.com/carapace-sh/carapace/pkg/ps"
+	"github.com/carapace-sh/carapace/pkg/settings"
 	"github.com/carapace-sh/carapace/pkg/style"
 	"github.com/spf13/cobra"
 )
@@ -36,11 +40,14 @@ func Snippet(cmd *cobra.Command, shell string) (string, error) {
 		"fish":       fish.Snippet,
 		"elvish":     elvish.Snippet,
 		"ion":        ion.Snippet,
+		"ksh":        ksh.Snippet,
+		"murex":      murex.Snippet,
 		"nushell":    nushell.Snippet,
 		"oil":        oil.Snippet,
 		"powershell": powershell.Snippet,
 		"tcsh":       tcsh.Snippet,
 		"xonsh":      xonsh.Snippet,
+		"yash":       yash.Snippet,
 		"zsh":        zsh.Snippet,
 	}
 	if s, ok := shellSnippets[shell]; ok {
@@ -63,33 +70,49 @@ func Value(shell string, value string, meta common.Meta, values common.RawValues
 		"elvish":     elvish.ActionRawValues,
 		"export":     export.ActionRawValues,
 		"ion":        ion.ActionRawValues,
+		"ksh":        ksh.ActionRawValues,
+		"murex":      murex.ActionRawValues,
 		"nushell":    nushell.ActionRawValues,
 		"oil":        oil.ActionRawValues,
 		"powershell": powershell.ActionRawValues,
 		"tcsh":       tcsh.ActionRawValues,
 		"xonsh":      xonsh.ActionRawValues,
+		"yash":       yash.ActionRawValues,
 		"zsh":        zsh.ActionRawValues,
 	}
 	if f, ok := shellFuncs[shell]; ok {
+		common.DescriptionWidth = settings.Carapace.DescriptionWidth
+		common.TerminalColumns = env.Columns()
 		if env.ColorDisabled() {
 			style.Carapace.Value = style.Default
 			style.Carapace.Description = style.Default
 			style.Carapace.Error = style.Underlined
+			style.Carapace.Warning = style.Underlined
+			style.Carapace.Info = style.Underlined
 			style.Carapace.Usage = style.Italic
 			values = values.Decolor()
+		} else if depth := style.DetectDepth(); depth < style.DepthTrueColor && shell != "export" { // keep export lossless
+			style.SetDepth(depth)
+			values = values.Downgrade(depth)
 		}
 		filtered := values.FilterPrefix(value)
 		switch shell {
-		case "elvish", "export", "zsh": // shells with support for showing messages
-		default:
+		case "bash", "ion", "ksh", "murex", "oil", "tcsh", "yash": // shells without support for showing messages
 			filtered = meta.Messages.Integrate(filtered, value)
+			if !meta.Messages.IsEmpty() {
+				meta.Nospace.Add('*') // don't insert a space after the messages shown as values
+			}
 		}
 
-		if !meta.Messages.IsEmpty() && shell != "export" {
-			meta.Nospace.Add('*')
+		switch shell {
+		case "export", "zsh": // shells with native support for insertion suffixes
+		default:
+			filtered = filtered.ApplyInsertSuffix()
 		}
 
-		sort.Sort(common.ByDisplay(filtered))
+		if !meta.KeepOrder {
+			sort.Sort(common.ByDisplay(filtered))
+		}
 		return f(value, meta, filtered)
 	}
 	return ""
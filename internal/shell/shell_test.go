@@ -0,0 +1,49 @@
+package shell
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carapace-sh/carapace/internal/common"
+)
+
+func TestValueMessages(t *testing.T) {
+	for _, tc := range []struct {
+		shell string
+		space string // formatted value with a trailing space
+	}{
+		{"bash-ble", "one\tone\x1c\x1c \x1c"},
+		{"fish", ""}, // suffix is determined by fish
+		{"nushell", `"one "`},
+		{"powershell", `"one "`},
+		{"xonsh", `"one "`},
+	} {
+		meta := common.Meta{Usage: "cmd [flags]"}
+		meta.Messages.Add("an error")
+
+		formatted := Value(tc.shell, "", meta, common.RawValuesFrom("one"))
+		if !strings.Contains(formatted, "an error") {
+			t.Errorf("%v: message should be shown [was: %#q]", tc.shell, formatted)
+		}
+		if !strings.Contains(formatted, tc.space) {
+			t.Errorf("%v: messages shown natively should not remove the space suffix [was: %#q]", tc.shell, formatted)
+		}
+
+		formatted = Value(tc.shell, "cu", common.Meta{Usage: "cmd [flags]"}, common.RawValues{})
+		if !strings.Contains(formatted, "cmd [flags]") {
+			t.Errorf("%v: usage should be shown [was: %#q]", tc.shell, formatted)
+		}
+	}
+}
+
+func TestValueMurex(t *testing.T) {
+	values := common.RawValues{
+		{Value: "b", Description: "first", Tag: "one"},
+		{Value: "a", Description: "second", Tag: "two"},
+		{Value: "b", Description: "third", Tag: "two"},
+	}
+	expected := `{"b ":"first (one), third (two)","a ":"second (two)"}`
+	if formatted := Value("murex", "", common.Meta{}, values); formatted != expected {
+		t.Errorf("expected %#q, got %#q", expected, formatted)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/carapace-sh/carapace/internal/common"
+	"github.com/carapace-sh/carapace/pkg/style"
 )
 
 var sanitizer = strings.NewReplacer( // TODO
@@ -19,33 +20,72 @@ type richCompletion struct {
 	Display     string
 	Description string
 	Style       string
+	// Message marks entries which are printed by the snippet (older snippets show them as candidates).
+	Message *string `json:",omitempty"`
 }
 
 // ActionRawValues formats values for xonsh.
 func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
-	vals := make([]richCompletion, len(values))
-	for index, val := range values {
-		val.Value = sanitizer.Replace(val.Value)
-
-		if strings.ContainsAny(val.Value, ` ()[]{}*$?\"|<>&;#`+"`") {
-			if strings.Contains(val.Value, `\`) {
-				val.Value = fmt.Sprintf("r'%v'", val.Value) // backslash needs raw string
-			} else {
-				val.Value = fmt.Sprintf("'%v'", val.Value)
-			}
-		}
+	vals := make([]richCompletion, 0, len(values))
+	for _, val := range values {
+		vals = append(vals, formatValue(val, meta))
+	}
 
-		if !meta.Nospace.Matches(val.Value) {
-			val.Value = val.Value + " "
+	existing := make(map[string]bool)
+	for _, val := range values {
+		existing[val.Value] = true
+	}
+	for _, val := range meta.Messages.Integrate(append(common.RawValues{}, values...), currentWord) {
+		if !existing[val.Value] {
+			message := ""
+			if val.Description != "" {
+				message = formatMessage(val.Description, val.Style)
+			}
+			rc := formatValue(val, meta)
+			rc.Message = &message
+			vals = append(vals, rc)
 		}
+	}
 
-		vals[index] = richCompletion{
-			Value:       val.Value,
-			Display:     val.Display,
-			Description: val.TrimmedDescription(),
-			Style:       convertStyle("bg-default fg-default " + val.Style),
-		}
+	if len(values) == 0 && meta.Usage != "" {
+		message := formatMessage(meta.Usage, style.Carapace.Usage)
+		vals = append(vals, richCompletion{
+			Value:       currentWord,
+			Display:     currentWord,
+			Description: sanitizer.Replace(meta.Usage),
+			Style:       convertStyle("bg-default fg-default " + style.Carapace.Usage),
+			Message:     &message,
+		})
 	}
+
 	m, _ := json.Marshal(vals)
 	return string(m)
 }
+
+func formatValue(val common.RawValue, meta common.Meta) richCompletion {
+	val.Value = sanitizer.Replace(val.Value)
+
+	if strings.ContainsAny(val.Value, ` ()[]{}*$?\"|<>&;#`+"`") {
+		if strings.Contains(val.Value, `\`) {
+			val.Value = fmt.Sprintf("r'%v'", val.Value) // backslash needs raw string
+		} else {
+			val.Value = fmt.Sprintf("'%v'", val.Value)
+		}
+	}
+
+	if !val.IsNospace(meta.Nospace) {
+		val.Value = val.Value + " "
+	}
+
+	return richCompletion{
+		Value:       val.Value,
+		Display:     val.Display,
+		Description: val.TrimmedDescription(),
+		Style:       convertStyle("bg-default fg-default " + val.Style),
+	}
+}
+
+func formatMessage(msg, _style string) string {
+	msg = strings.NewReplacer("\n", ``, "\r", ``, "\t", ``).Replace(msg)
+	return fmt.Sprintf("\x1b[%vm%v\x1b[%vm", style.SGR(_style), msg, style.SGR("fg-default"))
+}
@@ -0,0 +1,46 @@
+package yash
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/common"
+)
+
+var sanitizer = strings.NewReplacer(
+	"\n", ``,
+	"\r", ``,
+	"\t", ``,
+)
+
+// quote quotes given string for use in `eval`.
+func quote(s string) string {
+	return fmt.Sprintf("'%v'", strings.ReplaceAll(s, `'`, `'\''`))
+}
+
+// ActionRawValues formats values for yash as invocations of the `complete` builtin.
+func ActionRawValues(currentWord string, meta common.Meta, values common.RawValues) string {
+	lines := make([]string, 0, len(values))
+	for _, val := range values {
+		val.Value = sanitizer.Replace(val.Value)
+		val.Display = sanitizer.Replace(val.Display)
+
+		args := []string{"complete"}
+		if val.Description != "" {
+			args = append(args, "-D", quote(sanitizer.Replace(val.TrimmedDescription())))
+		}
+		if val.IsNospace(meta.Nospace) {
+			args = append(args, "-T")
+		}
+
+		candidate := val.Value
+		if prefix := strings.TrimSuffix(val.Value, val.Display); prefix != val.Value && prefix != "" && strings.HasPrefix(currentWord, prefix) {
+			// already completed prefix is not shown in the candidate list (e.g. multiparts)
+			args = append(args, "-P", quote(prefix))
+			candidate = val.Display
+		}
+		args = append(args, "--", quote(candidate))
+		lines = append(lines, strings.Join(args, " "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,17 @@
+// Package yash provides yash completion
+package yash
+
+import (
+	"fmt"
+
+	"github.com/carapace-sh/carapace/internal/uid"
+	"github.com/spf13/cobra"
+)
+
+// Snippet creates the yash completion script.
+func Snippet(cmd *cobra.Command) string {
+	return fmt.Sprintf(`completion/%v() {
+  eval "$(%v _carapace yash "${WORDS}" "${TARGETWORD}" 2>/dev/null)"
+}
+`, cmd.Name(), uid.Executable())
+}
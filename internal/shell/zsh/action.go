@@ -50,30 +50,52 @@ func ActionRawValues(currentWord string, meta common.Meta, values common.RawValu
 
 	tagGroup := make([]string, 0)
 	values.EachTag(func(tag string, values common.RawValues) {
-		vals := make([]string, len(values))
-		displays := make([]string, len(values))
-		for index, val := range values {
-			val.Value = sanitizer.Replace(val.Value)
-			val.Value = quoteValue(val.Value)
-			val.Value = strings.ReplaceAll(val.Value, `\`, `\\`) // TODO find out why `_describe` needs another backslash
-			val.Value = strings.ReplaceAll(val.Value, `:`, `\:`) // TODO find out why `_describe` needs another backslash
-			if !meta.Nospace.Matches(val.Value) {
-				val.Value = val.Value + " "
-			}
-			val.Display = sanitizer.Replace(val.Display)
-			val.Display = strings.ReplaceAll(val.Display, `\`, `\\`) // TODO find out why `_describe` needs another backslash
-			val.Display = strings.ReplaceAll(val.Display, `:`, `\:`) // TODO find out why `_describe` needs another backslash
-			val.Description = sanitizer.Replace(val.Description)
+		eachSuffix(values, func(insertSuffix, removeChars string, values common.RawValues) {
+			vals := make([]string, len(values))
+			displays := make([]string, len(values))
+			for index, val := range values {
+				nospace := val.IsNospace(meta.Nospace)
+				val.Value = sanitizer.Replace(val.Value)
+				val.Value = quoteValue(val.Value)
+				val.Value = strings.ReplaceAll(val.Value, `\`, `\\`) // TODO find out why `_describe` needs another backslash
+				val.Value = strings.ReplaceAll(val.Value, `:`, `\:`) // TODO find out why `_describe` needs another backslash
+				if !nospace {
+					val.Value = val.Value + " "
+				}
+				val.Display = sanitizer.Replace(val.Display)
+				val.Display = strings.ReplaceAll(val.Display, `\`, `\\`) // TODO find out why `_describe` needs another backslash
+				val.Display = strings.ReplaceAll(val.Display, `:`, `\:`) // TODO find out why `_describe` needs another backslash
+				val.Description = sanitizer.Replace(val.Description)
 
-			vals[index] = val.Value
+				vals[index] = val.Value
 
-			if strings.TrimSpace(val.Description) == "" {
-				displays[index] = val.Display
-			} else {
-				displays[index] = fmt.Sprintf("%v:%v", val.Display, val.Description)
+				if strings.TrimSpace(val.Description) == "" {
+					displays[index] = val.Display
+				} else {
+					displays[index] = fmt.Sprintf("%v:%v", val.Display, val.Description)
+				}
 			}
-		}
-		tagGroup = append(tagGroup, strings.Join([]string{tag, strings.Join(displays, "\n"), strings.Join(vals, "\n")}, "\003"))
+			tagGroup = append(tagGroup, strings.Join([]string{tag, sanitizer.Replace(insertSuffix), sanitizer.Replace(removeChars), strings.Join(displays, "\n"), strings.Join(vals, "\n")}, "\003"))
+		})
 	})
 	return fmt.Sprintf("%v\001%v\001%v\001", zstyles{values}.Format(), message{meta}.Format(), strings.Join(tagGroup, "\002")+"\002")
 }
+
+// eachSuffix groups values by insertion suffix (`compadd -S`) and remove characters (`compadd -r`).
+func eachSuffix(values common.RawValues, f func(insertSuffix, removeChars string, values common.RawValues)) {
+	type key struct{ insertSuffix, removeChars string }
+
+	keys := make([]key, 0)
+	groups := make(map[key]common.RawValues)
+	for _, val := range values {
+		k := key{val.InsertSuffix, val.RemoveChars}
+		if _, exists := groups[k]; !exists {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], val)
+	}
+
+	for _, k := range keys {
+		f(k.insertSuffix, k.removeChars, groups[k])
+	}
+}
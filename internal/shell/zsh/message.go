@@ -14,8 +14,8 @@ type message struct {
 
 func (m message) Format() string {
 	formatted := make([]string, 0)
-	for _, message := range m.Messages.Get() {
-		formatted = append(formatted, m.formatMessage(message, style.Carapace.Error))
+	for _, message := range m.Messages.List() {
+		formatted = append(formatted, m.formatMessage(message.Message, message.Severity.Style()))
 	}
 	if m.Usage != "" {
 		formatted = append(formatted, m.formatMessage(m.Usage, style.Carapace.Usage))
@@ -1,20 +1,40 @@
 package spec
 
+import "fmt"
+
 type Command struct {
-	Name            string            `yaml:"name"`
-	Aliases         []string          `yaml:"aliases,omitempty"`
-	Description     string            `yaml:"description,omitempty"`
-	Group           string            `yaml:"group,omitempty"`
-	Hidden          bool              `yaml:"hidden,omitempty"`
-	ExclusiveFlags  [][]string        `yaml:"exclusiveflags,omitempty"`
-	Flags           map[string]string `yaml:"flags,omitempty"`
-	PersistentFlags map[string]string `yaml:"persistentflags,omitempty"`
-	Completion      struct {
-		Flag          map[string][]string `yaml:"flag,omitempty"`
-		Positional    [][]string          `yaml:"positional,omitempty"`
-		PositionalAny []string            `yaml:"positionalany,omitempty"`
-		Dash          [][]string          `yaml:"dash,omitempty"`
-		DashAny       []string            `yaml:"dashany,omitempty"`
-	} `yaml:"completion,omitempty"`
-	Commands []Command `yaml:"commands,omitempty"`
+	Name                  string            `yaml:"name" json:"name"`
+	Aliases               []string          `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	Description           string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Group                 string            `yaml:"group,omitempty" json:"group,omitempty"`
+	Hidden                bool              `yaml:"hidden,omitempty" json:"hidden,omitempty"`
+	Args                  *Args             `yaml:"args,omitempty" json:"args,omitempty"`
+	ExclusiveFlags        [][]string        `yaml:"exclusiveflags,omitempty" json:"exclusiveflags,omitempty"`
+	RequiredTogetherFlags [][]string        `yaml:"requiredtogetherflags,omitempty" json:"requiredtogetherflags,omitempty"`
+	OneRequiredFlags      [][]string        `yaml:"onerequiredflags,omitempty" json:"onerequiredflags,omitempty"`
+	Flags                 map[string]string `yaml:"flags,omitempty" json:"flags,omitempty"`
+	PersistentFlags       map[string]string `yaml:"persistentflags,omitempty" json:"persistentflags,omitempty"`
+	Completion            Completion        `yaml:"completion,omitempty" json:"completion,omitempty"`
+	Commands              []Command         `yaml:"commands,omitempty" json:"commands,omitempty"`
+}
+
+// Args defines the amount of positional arguments (Max is -1 if unbounded).
+type Args struct {
+	Min int `yaml:"min" json:"min"`
+	Max int `yaml:"max" json:"max"`
+}
+
+func (a *Args) String() string {
+	if a.Max < 0 {
+		return fmt.Sprintf("%v..", a.Min)
+	}
+	return fmt.Sprintf("%v..%v", a.Min, a.Max)
+}
+
+type Completion struct {
+	Flag          map[string][]string `yaml:"flag,omitempty" json:"flag,omitempty"`
+	Positional    [][]string          `yaml:"positional,omitempty" json:"positional,omitempty"`
+	PositionalAny []string            `yaml:"positionalany,omitempty" json:"positionalany,omitempty"`
+	Dash          [][]string          `yaml:"dash,omitempty" json:"dash,omitempty"`
+	DashAny       []string            `yaml:"dashany,omitempty" json:"dashany,omitempty"`
 }
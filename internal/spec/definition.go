@@ -0,0 +1,71 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Definition is a parsed flag definition.
+type Definition struct {
+	Name       string
+	Shorthand  string
+	Mode       int // see pflagfork
+	Value      bool
+	Optarg     bool
+	Repeatable bool
+	Required   bool
+	Hidden     bool
+}
+
+// ParseDefinition parses the format of `pflagfork.Flag.Definition`.
+//
+//	-f, --file=
+//	--verbose*
+//	-v&
+//	-s, -long?
+func ParseDefinition(s string) (d Definition, err error) {
+	names := strings.TrimRight(s, "&!*?=")
+	for _, r := range s[len(names):] {
+		switch r {
+		case '&':
+			d.Hidden = true
+		case '!':
+			d.Required = true
+		case '*':
+			d.Repeatable = true
+		case '?':
+			d.Value = true
+			d.Optarg = true
+		case '=':
+			d.Value = true
+		}
+	}
+
+	parts := strings.Split(names, ",")
+	for index, part := range parts {
+		parts[index] = strings.TrimSpace(part)
+	}
+
+	switch {
+	case len(parts) == 1 && strings.HasPrefix(parts[0], "--"):
+		d.Name = strings.TrimPrefix(parts[0], "--")
+	case len(parts) == 1 && strings.HasPrefix(parts[0], "-"):
+		d.Shorthand = strings.TrimPrefix(parts[0], "-")
+		d.Name = d.Shorthand
+		d.Mode = 1 // ShorthandOnly
+	case len(parts) == 2 && strings.HasPrefix(parts[0], "-") && strings.HasPrefix(parts[1], "--"):
+		d.Shorthand = strings.TrimPrefix(parts[0], "-")
+		d.Name = strings.TrimPrefix(parts[1], "--")
+	case len(parts) == 2 && strings.HasPrefix(parts[0], "-") && strings.HasPrefix(parts[1], "-"):
+		d.Shorthand = strings.TrimPrefix(parts[0], "-")
+		d.Name = strings.TrimPrefix(parts[1], "-")
+		d.Mode = 2 // NameAsShorthand
+	default:
+		return d, fmt.Errorf("invalid flag definition: %v", s)
+	}
+
+	if d.Name == "" || strings.HasPrefix(d.Name, "-") || len(d.Shorthand) > 1 || (d.Shorthand != "" && strings.HasPrefix(d.Shorthand, "-")) {
+		return d, fmt.Errorf("invalid flag definition: %v", s)
+	}
+	return d, nil
+}
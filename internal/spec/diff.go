@@ -0,0 +1,304 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/carapace-sh/carapace/third_party/github.com/hexops/gotextdiff"
+	"github.com/carapace-sh/carapace/third_party/github.com/hexops/gotextdiff/myers"
+	"github.com/carapace-sh/carapace/third_party/github.com/hexops/gotextdiff/span"
+	"gopkg.in/yaml.v3"
+)
+
+// Change is a difference between two specs.
+type Change struct {
+	Breaking bool
+	Path     string // command path with optional flag (e.g. `example sub --flag`)
+	Message  string
+}
+
+func (c Change) String() string {
+	if c.Breaking {
+		return fmt.Sprintf("breaking: %v: %v", c.Path, c.Message)
+	}
+	return fmt.Sprintf("non-breaking: %v: %v", c.Path, c.Message)
+}
+
+// HasBreaking checks whether any of given changes is breaking.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Unified returns a unified diff of given specs.
+func Unified(oldName, newName, oldSpec, newSpec string) string {
+	edits := myers.ComputeEdits(span.URIFromPath(""), oldSpec, newSpec)
+	return fmt.Sprint(gotextdiff.ToUnified(oldName, newName, oldSpec, edits))
+}
+
+// Compare classifies the changes between given command trees.
+func Compare(oldCmd, newCmd Command) []Change {
+	changes := make([]Change, 0)
+	compare(&changes, commandName(oldCmd), oldCmd, newCmd)
+	return changes
+}
+
+func compare(changes *[]Change, path string, oldCmd, newCmd Command) {
+	add := func(breaking bool, path string, format string, args ...interface{}) {
+		*changes = append(*changes, Change{Breaking: breaking, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, alias := range difference(oldCmd.Aliases, newCmd.Aliases) {
+		add(true, path, "removed alias %v", alias)
+	}
+	for _, alias := range difference(newCmd.Aliases, oldCmd.Aliases) {
+		add(false, path, "added alias %v", alias)
+	}
+
+	compareArgs(add, path, oldCmd.Args, newCmd.Args)
+	compareFlags(add, path, oldCmd, newCmd)
+
+	for _, g := range []struct {
+		name     string
+		old, new [][]string
+	}{
+		{"exclusive", oldCmd.ExclusiveFlags, newCmd.ExclusiveFlags},
+		{"required together", oldCmd.RequiredTogetherFlags, newCmd.RequiredTogetherFlags},
+		{"one required", oldCmd.OneRequiredFlags, newCmd.OneRequiredFlags},
+	} {
+		oldGroups, newGroups := joinGroups(g.old), joinGroups(g.new)
+		for _, group := range difference(newGroups, oldGroups) {
+			add(true, path, "added %v flag group [%v]", g.name, group)
+		}
+		for _, group := range difference(oldGroups, newGroups) {
+			add(false, path, "removed %v flag group [%v]", g.name, group)
+		}
+	}
+
+	compareValues(add, path+" <positionalany>", oldCmd.Completion.PositionalAny, newCmd.Completion.PositionalAny)
+	for index, values := range oldCmd.Completion.Positional {
+		if index < len(newCmd.Completion.Positional) {
+			compareValues(add, fmt.Sprintf("%v <positional%v>", path, index+1), values, newCmd.Completion.Positional[index])
+		}
+	}
+
+	newCommands := make(map[string]Command)
+	for _, subCmd := range newCmd.Commands {
+		newCommands[commandName(subCmd)] = subCmd
+	}
+
+	oldCommands := make(map[string]bool)
+	for _, oldSubCmd := range oldCmd.Commands {
+		name := commandName(oldSubCmd)
+		oldCommands[name] = true
+		if newSubCmd, ok := newCommands[name]; ok {
+			if !oldSubCmd.Hidden && newSubCmd.Hidden {
+				add(false, path+" "+name, "command is now hidden")
+			}
+			compare(changes, path+" "+name, oldSubCmd, newSubCmd)
+		} else {
+			add(true, path+" "+name, "removed command")
+		}
+	}
+
+	for _, subCmd := range newCmd.Commands {
+		if name := commandName(subCmd); !oldCommands[name] {
+			add(false, path+" "+name, "added command")
+		}
+	}
+}
+
+func compareArgs(add func(bool, string, string, ...interface{}), path string, oldArgs, newArgs *Args) {
+	if oldArgs == nil || newArgs == nil || *oldArgs == *newArgs {
+		return
+	}
+
+	narrowed := newArgs.Min > oldArgs.Min ||
+		(newArgs.Max >= 0 && (oldArgs.Max < 0 || newArgs.Max < oldArgs.Max))
+	add(narrowed, path, "changed positional arguments from %v to %v", oldArgs, newArgs)
+}
+
+func compareFlags(add func(bool, string, string, ...interface{}), path string, oldCmd, newCmd Command) {
+	oldFlags, newFlags := definitions(oldCmd), definitions(newCmd)
+
+	names := make([]string, 0, len(oldFlags))
+	for name := range oldFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		o := oldFlags[name]
+		flagPath := path + " --" + name
+		n, ok := newFlags[name]
+		if !ok {
+			add(true, flagPath, "removed flag")
+			continue
+		}
+
+		switch {
+		case o.persistent && !n.persistent:
+			add(true, flagPath, "no longer persistent")
+		case !o.persistent && n.persistent:
+			add(false, flagPath, "now persistent")
+		}
+
+		switch {
+		case o.Shorthand != "" && n.Shorthand == "":
+			add(true, flagPath, "removed shorthand -%v", o.Shorthand)
+		case o.Shorthand != n.Shorthand && o.Shorthand != "":
+			add(true, flagPath, "changed shorthand from -%v to -%v", o.Shorthand, n.Shorthand)
+		case o.Shorthand != n.Shorthand:
+			add(false, flagPath, "added shorthand -%v", n.Shorthand)
+		}
+
+		switch {
+		case !o.Value && n.Value && !n.Optarg:
+			add(true, flagPath, "now requires a value")
+		case o.Optarg && n.Value && !n.Optarg:
+			add(true, flagPath, "now requires a value")
+		case o.Value && !n.Value:
+			add(true, flagPath, "no longer takes a value")
+		case !o.Value && n.Optarg:
+			add(false, flagPath, "now takes an optional value")
+		case !o.Optarg && n.Optarg:
+			add(false, flagPath, "value is now optional")
+		}
+
+		switch {
+		case o.Repeatable && !n.Repeatable:
+			add(true, flagPath, "no longer repeatable")
+		case !o.Repeatable && n.Repeatable:
+			add(false, flagPath, "now repeatable")
+		}
+
+		switch {
+		case !o.Required && n.Required:
+			add(true, flagPath, "now required")
+		case o.Required && !n.Required:
+			add(false, flagPath, "no longer required")
+		}
+
+		if !o.Hidden && n.Hidden {
+			add(false, flagPath, "flag is now hidden")
+		}
+
+		compareValues(add, flagPath, oldCmd.Completion.Flag[name], newCmd.Completion.Flag[name])
+	}
+
+	names = names[:0]
+	for name := range newFlags {
+		if _, ok := oldFlags[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if newFlags[name].Required {
+			add(true, path+" --"+name, "added required flag")
+		} else {
+			add(false, path+" --"+name, "added flag")
+		}
+	}
+}
+
+// compareValues compares static values (macros and descriptions are ignored).
+func compareValues(add func(bool, string, string, ...interface{}), path string, oldValues, newValues []string) {
+	o, n := staticValues(oldValues), staticValues(newValues)
+	if len(o) == 0 || len(n) == 0 {
+		return // not static or no longer static
+	}
+
+	for _, value := range difference(o, n) {
+		add(true, path, "removed value %v", value)
+	}
+	for _, value := range difference(n, o) {
+		add(false, path, "added value %v", value)
+	}
+}
+
+func staticValues(values []string) []string {
+	static := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.HasPrefix(value, "$") {
+			continue // macro
+		}
+		static = append(static, strings.SplitN(value, "\t", 2)[0])
+	}
+	return static
+}
+
+type flagDefinition struct {
+	Definition
+	persistent bool
+}
+
+// definitions returns the parsed local and persistent flags by name.
+func definitions(cmd Command) map[string]flagDefinition {
+	d := make(map[string]flagDefinition)
+	for _, f := range []struct {
+		flags      map[string]string
+		persistent bool
+	}{
+		{cmd.Flags, false},
+		{cmd.PersistentFlags, true},
+	} {
+		for definition := range f.flags {
+			if parsed, err := ParseDefinition(definition); err == nil {
+				d[parsed.Name] = flagDefinition{parsed, f.persistent}
+			}
+		}
+	}
+	return d
+}
+
+func commandName(cmd Command) string {
+	if fields := strings.Fields(cmd.Name); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+func joinGroups(groups [][]string) []string {
+	joined := make([]string, 0, len(groups))
+	for _, group := range groups {
+		sorted := append([]string{}, group...)
+		sort.Strings(sorted)
+		joined = append(joined, strings.Join(sorted, " "))
+	}
+	return joined
+}
+
+// difference returns the elements of a not present in b.
+func difference(a, b []string) []string {
+	m := make(map[string]bool, len(b))
+	for _, e := range b {
+		m[e] = true
+	}
+
+	d := make([]string, 0)
+	for _, e := range a {
+		if !m[e] {
+			d = append(d, e)
+		}
+	}
+	return d
+}
+
+// CompareYaml is like Compare but parses given specs first.
+func CompareYaml(oldSpec, newSpec []byte) ([]Change, error) {
+	var oldCmd, newCmd Command
+	if err := yaml.Unmarshal(oldSpec, &oldCmd); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(newSpec, &newCmd); err != nil {
+		return nil, err
+	}
+	return Compare(oldCmd, newCmd), nil
+}
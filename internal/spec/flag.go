@@ -0,0 +1,52 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds a flag parsed from given definition (e.g. `-f, --file=`).
+func AddFlag(fs *pflag.FlagSet, definition, usage string) error {
+	d, err := ParseDefinition(definition)
+	if err != nil {
+		return err
+	}
+
+	if fs.Lookup(d.Name) != nil || (d.Shorthand != "" && fs.ShorthandLookup(d.Shorthand) != nil) {
+		return fmt.Errorf("duplicate flag: %v", definition)
+	}
+
+	switch {
+	case d.Value && d.Repeatable:
+		fs.StringArrayP(d.Name, d.Shorthand, nil, usage)
+	case d.Value:
+		fs.StringP(d.Name, d.Shorthand, "", usage)
+	case d.Repeatable:
+		fs.CountP(d.Name, d.Shorthand, usage)
+	default:
+		fs.BoolP(d.Name, d.Shorthand, false, usage)
+	}
+
+	flag := fs.Lookup(d.Name)
+	flag.Hidden = d.Hidden
+	if d.Optarg {
+		flag.NoOptDefVal = " "
+	}
+	if d.Required {
+		if err := fs.SetAnnotation(d.Name, cobra.BashCompOneRequiredFlag, []string{"true"}); err != nil {
+			return err
+		}
+	}
+	setMode(flag, d.Mode)
+	return nil
+}
+
+// setMode sets the representation mode if the pflag fork is used.
+func setMode(flag *pflag.Flag, mode int) {
+	if field := reflect.ValueOf(flag).Elem().FieldByName("Mode"); field.IsValid() && field.Kind() == reflect.Int && field.CanSet() {
+		field.SetInt(int64(mode))
+	}
+}
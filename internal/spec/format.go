@@ -0,0 +1,109 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format formats given command as `json`, `markdown` or `yaml`.
+func Format(c Command, format string) (string, error) {
+	switch format {
+	case "json":
+		m, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(m) + "\n", nil
+	case "markdown":
+		return Markdown(c), nil
+	case "yaml", "":
+		m, err := yaml.Marshal(c)
+		if err != nil {
+			return "", err
+		}
+		return "# yaml-language-server: $schema=https://carapace.sh/schemas/command.json\n" + string(m), nil
+	default:
+		return "", fmt.Errorf("unknown format: %v", format)
+	}
+}
+
+// Markdown generates a reference of given command and its subcommands.
+func Markdown(c Command) string {
+	var b strings.Builder
+	markdown(&b, commandName(c), c, 1)
+	return b.String()
+}
+
+func markdown(b *strings.Builder, path string, c Command, level int) {
+	fmt.Fprintf(b, "%v %v\n\n", strings.Repeat("#", level), path)
+	if c.Description != "" {
+		fmt.Fprintf(b, "%v\n\n", c.Description)
+	}
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(b, "Aliases: %v\n\n", code(c.Aliases...))
+	}
+	if c.Args != nil {
+		fmt.Fprintf(b, "Arguments: `%v`\n\n", c.Args)
+	}
+
+	type row struct{ name, definition, usage string }
+	rows := make([]row, 0)
+	for _, flags := range []map[string]string{c.Flags, c.PersistentFlags} {
+		for definition, usage := range flags {
+			if d, err := ParseDefinition(definition); err == nil {
+				rows = append(rows, row{d.Name, definition, usage})
+			}
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].name < rows[j].name })
+
+	if len(rows) > 0 {
+		b.WriteString("| Flag | Definition | Usage | Completion |\n")
+		b.WriteString("| ---- | ---------- | ----- | ---------- |\n")
+		for _, r := range rows {
+			fmt.Fprintf(b, "| %v | %v | %v | %v |\n", r.name, code(r.definition), cell(r.usage), hints(c.Completion.Flag[r.name]))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(c.Completion.Positional) > 0 || len(c.Completion.PositionalAny) > 0 {
+		b.WriteString("| Position | Completion |\n")
+		b.WriteString("| -------- | ---------- |\n")
+		for index, values := range c.Completion.Positional {
+			fmt.Fprintf(b, "| %v | %v |\n", index+1, hints(values))
+		}
+		if len(c.Completion.PositionalAny) > 0 {
+			fmt.Fprintf(b, "| %v.. | %v |\n", len(c.Completion.Positional)+1, hints(c.Completion.PositionalAny))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, subCmd := range c.Commands {
+		markdown(b, path+" "+commandName(subCmd), subCmd, 2)
+	}
+}
+
+// hints returns the values of a completion (without descriptions).
+func hints(values []string) string {
+	h := make([]string, 0, len(values))
+	for _, value := range values {
+		h = append(h, strings.SplitN(value, "\t", 2)[0])
+	}
+	return code(h...)
+}
+
+func code(s ...string) string {
+	c := make([]string, 0, len(s))
+	for _, e := range s {
+		c = append(c, "`"+cell(e)+"`")
+	}
+	return strings.Join(c, ", ")
+}
+
+func cell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
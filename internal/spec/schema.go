@@ -0,0 +1,70 @@
+package spec
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Schema generates the JSON Schema of Command from its Go types.
+func Schema() string {
+	s := schema(reflect.TypeOf(Command{}), true)
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["$id"] = "https://carapace.sh/schemas/command.json"
+	s["title"] = "command"
+
+	m, _ := json.MarshalIndent(s, "", "  ")
+	return string(m) + "\n"
+}
+
+func schema(t reflect.Type, root bool) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schema(t.Elem(), false)
+	case reflect.Struct:
+		if t == reflect.TypeOf(Command{}) && !root {
+			return map[string]interface{}{"$ref": "#"} // recursive subcommands
+		}
+
+		properties := make(map[string]interface{})
+		required := make([]string, 0)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			splitted := strings.Split(field.Tag.Get("yaml"), ",")
+			if splitted[0] == "" || splitted[0] == "-" {
+				continue
+			}
+
+			properties[splitted[0]] = schema(field.Type, false)
+			if len(splitted) == 1 {
+				required = append(required, splitted[0])
+			}
+		}
+
+		s := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schema(t.Elem(), false),
+		}
+	case reflect.Slice:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schema(t.Elem(), false),
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int:
+		return map[string]interface{}{"type": "integer"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
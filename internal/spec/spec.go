@@ -2,31 +2,51 @@
 package spec
 
 import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
 	"github.com/carapace-sh/carapace/internal/pflagfork"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"gopkg.in/yaml.v3"
 )
 
+// AnnotationArgs is the command annotation containing the arity (`min max`).
+const AnnotationArgs = "carapace_args"
+
 // Spec generates the spec file.
-func Spec(cmd *cobra.Command) string {
-	m, _ := yaml.Marshal(command(cmd))
-	return "# yaml-language-server: $schema=https://carapace.sh/schemas/command.json\n" + string(m)
+// The completion function provides the symbolic representation of registered completions.
+func Spec(cmd *cobra.Command, completion func(cmd *cobra.Command) Completion) string {
+	s, _ := Format(New(cmd, completion), "yaml")
+	return s
+}
+
+// New creates the spec of given command.
+func New(cmd *cobra.Command, completion func(cmd *cobra.Command) Completion) Command {
+	return command(cmd, completion)
 }
 
-func command(cmd *cobra.Command) Command {
+func command(cmd *cobra.Command, completion func(cmd *cobra.Command) Completion) Command {
 	c := Command{
-		Name:            cmd.Use,
-		Description:     cmd.Short,
-		Aliases:         cmd.Aliases,
-		Group:           cmd.GroupID,
-		Hidden:          cmd.Hidden,
-		Flags:           make(map[string]string),
-		PersistentFlags: make(map[string]string),
-		Commands:        make([]Command, 0),
+		Name:                  cmd.Use,
+		Description:           cmd.Short,
+		Aliases:               cmd.Aliases,
+		Group:                 cmd.GroupID,
+		Hidden:                cmd.Hidden,
+		Args:                  args(cmd),
+		ExclusiveFlags:        flagGroups(cmd, "cobra_annotation_mutually_exclusive"),
+		RequiredTogetherFlags: flagGroups(cmd, "cobra_annotation_required_if_others_set"),
+		OneRequiredFlags:      flagGroups(cmd, "cobra_annotation_one_required"),
+		Flags:                 make(map[string]string),
+		PersistentFlags:       make(map[string]string),
+		Commands:              make([]Command, 0),
 	}
 
-	// TODO mutually exclusive flags
+	if completion != nil {
+		c.Completion = completion(cmd)
+	}
 
 	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
 		if cmd.PersistentFlags().Lookup(flag.Name) != nil {
@@ -46,9 +66,63 @@ func command(cmd *cobra.Command) Command {
 
 	for _, subcmd := range cmd.Commands() {
 		if subcmd.Name() != "_carapace" && subcmd.Deprecated == "" {
-			c.Commands = append(c.Commands, command(subcmd))
+			c.Commands = append(c.Commands, command(subcmd, completion))
 		}
 	}
 
 	return c
 }
+
+// flagGroups returns the flag groups of given cobra annotation (e.g. from `MarkFlagsMutuallyExclusive`).
+// Inherited flags are included as cobra validates groups containing them as well.
+func flagGroups(cmd *cobra.Command, annotation string) [][]string {
+	unique := make(map[string]bool)
+	cmd.InheritedFlags() // merges persistent flags of parent commands
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		for _, group := range flag.Annotations[annotation] {
+			unique[group] = true
+		}
+	})
+
+	groups := make([][]string, 0, len(unique))
+	for group := range unique {
+		names := strings.Fields(group)
+		for _, name := range names {
+			if cmd.Flags().Lookup(name) == nil {
+				names = nil // group of a subcommand sharing an inherited flag
+				break
+			}
+		}
+		if names != nil {
+			groups = append(groups, names)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.Join(groups[i], " ") < strings.Join(groups[j], " ")
+	})
+	return groups
+}
+
+// args returns the arity set with `carapace.Gen(cmd).Args` or that of known cobra validators.
+// Other validators are not invoked, so nil is returned.
+func args(cmd *cobra.Command) *Args {
+	if value, ok := cmd.Annotations[AnnotationArgs]; ok {
+		var a Args
+		if _, err := fmt.Sscanf(value, "%d %d", &a.Min, &a.Max); err != nil {
+			return nil
+		}
+		return &a
+	}
+
+	if cmd.Args == nil {
+		return nil
+	}
+	switch runtime.FuncForPC(reflect.ValueOf(cmd.Args).Pointer()).Name() {
+	case "github.com/spf13/cobra.NoArgs":
+		return &Args{Min: 0, Max: 0}
+	case "github.com/spf13/cobra.ArbitraryArgs":
+		return &Args{Min: 0, Max: -1}
+	default:
+		return nil
+	}
+}
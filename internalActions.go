@@ -6,8 +6,9 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/carapace-sh/carapace/internal/env"
+	"github.com/carapace-sh/carapace/internal/config"
 	"github.com/carapace-sh/carapace/internal/pflagfork"
+	"github.com/carapace-sh/carapace/pkg/settings"
 	"github.com/carapace-sh/carapace/pkg/style"
 	"github.com/carapace-sh/carapace/pkg/util"
 	"github.com/spf13/cobra"
@@ -86,7 +87,7 @@ func actionFlags(cmd *cobra.Command) Action {
 		vals := make([]string, 0)
 		flagSet.VisitAll(func(f *pflagfork.Flag) {
 			switch {
-			case f.Hidden && !env.Hidden():
+			case f.Hidden && !settings.Carapace.Hidden:
 				return // skip hidden flags
 			case f.Deprecated != "":
 				return // skip deprecated flags
@@ -148,3 +149,76 @@ func initHelpCompletion(cmd *cobra.Command) {
 		ActionCommands(cmd),
 	)
 }
+
+// actionSettings completes setting keys.
+//
+//	carapace.Hidden
+//	carapace.Timeout
+func actionSettings() Action {
+	return ActionMultiParts(".", func(c Context) Action {
+		switch len(c.Parts) {
+		case 0:
+			return ActionValues(config.GetSettingConfigs()...).Invoke(c).Suffix(".").ToA().NoSpace()
+		case 1:
+			fields, err := config.GetSettingFields(c.Parts[0])
+			if err != nil {
+				return ActionMessage(err.Error())
+			}
+			batch := Batch()
+			for _, field := range fields {
+				batch = append(batch, ActionValuesDescribed(field.Name, field.Description).Tag(field.Tag))
+			}
+			return batch.ToA()
+		default:
+			return ActionValues()
+		}
+	})
+}
+
+// actionSettingValue completes the value of given setting key.
+func actionSettingValue(key string) Action {
+	return ActionCallback(func(c Context) Action {
+		field, err := config.GetSettingField(key)
+		if err != nil {
+			return ActionMessage(err.Error())
+		}
+
+		switch {
+		case key == "carapace.Theme":
+			return ActionValues(config.GetThemes()...).Usage(field.Description)
+		case len(field.Values) > 0:
+			return ActionValues(field.Values...).Usage(field.Description)
+		case field.Type == "bool":
+			return ActionValues("true", "false").StyleF(style.ForKeyword).Usage(field.Description)
+		default:
+			return ActionValues().Usage(field.Description)
+		}
+	})
+}
+
+// actionStyleKeys completes style keys with given suffix.
+//
+//	carapace.Value
+//	carapace.Description
+func actionStyleKeys(suffix string) Action {
+	return ActionMultiParts(".", func(c Context) Action {
+		switch len(c.Parts) {
+		case 0:
+			return ActionValues(config.GetStyleConfigs()...).Invoke(c).Suffix(".").ToA()
+
+		case 1:
+			fields, err := config.GetStyleFields(c.Parts[0])
+			if err != nil {
+				return ActionMessage(err.Error())
+			}
+			batch := Batch()
+			for _, field := range fields {
+				batch = append(batch, ActionStyledValuesDescribed(field.Name, field.Description, field.Style).Tag(field.Tag))
+			}
+			return batch.Invoke(c).Merge().Suffix(suffix).ToA()
+
+		default:
+			return ActionValues()
+		}
+	})
+}
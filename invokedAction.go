@@ -6,6 +6,7 @@ import (
 	"github.com/carapace-sh/carapace/internal/common"
 	"github.com/carapace-sh/carapace/internal/export"
 	_shell "github.com/carapace-sh/carapace/internal/shell"
+	"github.com/carapace-sh/carapace/pkg/i18n"
 	"github.com/carapace-sh/carapace/pkg/match"
 )
 
@@ -108,11 +109,14 @@ func (ia InvokedAction) ToMultiPartsA(dividers ...string) Action {
 
 					if len(splitted) == len(splittedCV) {
 						uniqueVals[v] = common.RawValue{
-							Value:       v,
-							Display:     d,
-							Description: val.Description,
-							Style:       val.Style,
-							Tag:         val.Tag,
+							Value:        v,
+							Display:      d,
+							Description:  val.Description,
+							Style:        val.Style,
+							Tag:          val.Tag,
+							Nospace:      val.Nospace,
+							InsertSuffix: val.InsertSuffix,
+							RemoveChars:  val.RemoveChars,
 						}
 					} else {
 						uniqueVals[v] = common.RawValue{
@@ -145,6 +149,20 @@ func (ia InvokedAction) ToMultiPartsA(dividers ...string) Action {
 	})
 }
 
+// localize translates descriptions, usage and messages to the language of given context.
+func (ia InvokedAction) localize(c Context) InvokedAction {
+	lang := i18n.Language(c.Getenv)
+	if lang == "" {
+		return ia
+	}
+
+	translate := func(s string) string { return i18n.Translate(lang, s) }
+	ia.action.rawValues = ia.action.rawValues.Translate(translate)
+	ia.action.meta.Usage = translate(ia.action.meta.Usage)
+	ia.action.meta.Messages = ia.action.meta.Messages.Translate(translate)
+	return ia
+}
+
 func (ia InvokedAction) value(shell string, value string) string {
 	return _shell.Value(shell, value, ia.action.meta, ia.action.rawValues)
 }
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/carapace-sh/carapace/pkg/i18n"
 	"github.com/carapace-sh/carapace/pkg/style"
 )
 
@@ -39,3 +40,28 @@ func TestToMultiParts(t *testing.T) {
 
 	_test("C/d/1", `{"value":"C/d/1()2","display":"1()2","description":"withbrackets","style":"yellow"}`, "/")
 }
+
+func TestLocalize(t *testing.T) {
+	i18n.Register("xx", i18n.Catalog{
+		"one":                  "eins",
+		"usage":                "Verwendung",
+		"missing value for %v": "fehlender Wert für %v",
+	})
+
+	c := Context{Env: []string{"LANG=xx_YY.UTF-8"}}
+	a := ActionValuesDescribed("1", "one", "2", "two").Usage("usage")
+	actual := a.Invoke(c).localize(c).value("export", "")
+	for _, expected := range []string{`"description":"eins"`, `"description":"two"`, `"usage":"Verwendung"`} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("expected '%v' in '%v'", expected, actual)
+		}
+	}
+
+	if actual := ActionMessage("missing value for %v", "--flag").Invoke(c).localize(c).value("export", ""); !strings.Contains(actual, `fehlender Wert für --flag`) {
+		t.Errorf("unexpected message: %v", actual)
+	}
+
+	if actual := a.Invoke(Context{}).localize(Context{}).value("export", ""); !strings.Contains(actual, `"description":"one"`) {
+		t.Errorf("expected fallback to original: %v", actual)
+	}
+}
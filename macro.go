@@ -0,0 +1,162 @@
+package carapace
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+type macro struct {
+	action      func(args ...string) Action
+	description string
+}
+
+var macros = struct {
+	sync.RWMutex
+	entries map[string]macro
+}{
+	entries: map[string]macro{
+		"directories": {func(args ...string) Action { return ActionDirectories() }, "directories"},
+		"executables": {func(args ...string) Action { return ActionExecutables() }, "executables in PATH"},
+		"files":       {func(args ...string) Action { return ActionFiles(args...) }, "files with optional suffixes"},
+	},
+}
+
+var (
+	macroName    = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`)
+	macroPattern = regexp.MustCompile(`^\$([a-zA-Z_][a-zA-Z0-9_.-]*)(\((.*)\))?$`)
+	execPattern  = regexp.MustCompile(`^\$\((.*)\)$`)
+)
+
+// RegisterMacro registers an Action by name so that it can be used in specs, overlays
+// and by other programs with `_carapace macro <name> [args] -- <words>`.
+// It panics if the name is invalid.
+//
+//	carapace.RegisterMacro("services", func(args ...string) carapace.Action {
+//		return carapace.ActionValues(services(args...)...)
+//	}, "deployable services")
+func RegisterMacro(name string, f func(args ...string) Action, description string) {
+	if !macroName.MatchString(name) {
+		panic(fmt.Sprintf("invalid macro name: %#v", name))
+	}
+
+	macros.Lock()
+	defer macros.Unlock()
+	macros.entries[name] = macro{f, description}
+}
+
+func lookupMacro(name string) (macro, bool) {
+	macros.RLock()
+	defer macros.RUnlock()
+	m, ok := macros.entries[name]
+	return m, ok
+}
+
+// actionMacros completes registered macros.
+func actionMacros() Action {
+	return ActionCallback(func(c Context) Action {
+		macros.RLock()
+		defer macros.RUnlock()
+
+		vals := make([]string, 0, len(macros.entries)*2)
+		for name, m := range macros.entries {
+			vals = append(vals, name, m.description)
+		}
+		return ActionValuesDescribed(vals...)
+	})
+}
+
+// ActionSpec completes values in the format used by spec files.
+//
+//	value\tdescription  // static value with optional description
+//	\$value             // static value with escaped macro prefix
+//	$files([.go, .mod]) // macro with optional arguments
+//	$(git tag)          // output of a shell command (`value\tdescription` per line)
+//
+//	carapace.ActionSpec("one\tfirst", "two", "$directories")
+func ActionSpec(values ...string) Action {
+	batch := Batch()
+	vals := make([]string, 0)
+	for _, value := range values {
+		if matches := execPattern.FindStringSubmatch(value); matches != nil {
+			batch = append(batch, actionShell(matches[1]))
+			continue
+		}
+
+		if matches := macroPattern.FindStringSubmatch(value); matches != nil {
+			batch = append(batch, actionMacro(matches[1], matches[3]))
+			continue
+		}
+
+		if strings.HasPrefix(value, `\$`) {
+			value = value[1:] // escaped macro prefix
+		}
+		splitted := strings.SplitN(value, "\t", 2)
+		if len(splitted) == 1 {
+			splitted = append(splitted, "")
+		}
+		vals = append(vals, splitted...)
+	}
+	return append(batch, ActionValuesDescribed(vals...)).ToA()
+}
+
+func actionMacro(name, arg string) Action {
+	args, err := macroArgs(name, arg)
+	if err != nil {
+		return ActionMessage(err.Error())
+	}
+	m, _ := lookupMacro(name)
+	return m.action(args...)
+}
+
+// macroArgs parses the arguments of given macro (yaml).
+func macroArgs(name, arg string) ([]string, error) {
+	if _, ok := lookupMacro(name); !ok {
+		return nil, fmt.Errorf("unknown macro: %v", name)
+	}
+
+	var args []string
+	if arg != "" {
+		if err := yaml.Unmarshal([]byte(arg), &args); err != nil {
+			return nil, fmt.Errorf("invalid argument for macro %v: %v", name, err.Error())
+		}
+	}
+	return args, nil
+}
+
+// actionShell completes the output of given shell command (`value\tdescription` per line).
+func actionShell(command string) Action {
+	return ActionExecCommand("sh", "-c", command)(func(output []byte) Action {
+		vals := make([]string, 0)
+		for _, line := range strings.Split(string(output), "\n") {
+			if line == "" {
+				continue
+			}
+			splitted := strings.SplitN(line, "\t", 2)
+			if len(splitted) == 1 {
+				splitted = append(splitted, "")
+			}
+			vals = append(vals, splitted...)
+		}
+		return ActionValuesDescribed(vals...)
+	})
+}
+
+// checkSpec returns errors for unknown macros and invalid macro arguments.
+func checkSpec(values []string) []string {
+	errors := make([]string, 0)
+	for _, value := range values {
+		if execPattern.MatchString(value) {
+			continue
+		}
+		if matches := macroPattern.FindStringSubmatch(value); matches != nil {
+			if _, err := macroArgs(matches[1], matches[3]); err != nil {
+				errors = append(errors, err.Error())
+			}
+		}
+	}
+	return errors
+}
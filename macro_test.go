@@ -0,0 +1,62 @@
+package carapace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RegisterMacro("test.args", func(args ...string) Action {
+		return ActionCallback(func(c Context) Action {
+			return ActionValues(append(args, c.Args...)...)
+		})
+	}, "arguments and words")
+}
+
+func TestRegisterMacro(t *testing.T) {
+	assertEqual(t,
+		ActionValues("one", "two", "three").Invoke(Context{}),
+		ActionSpec("$test.args([one, two])", "three").Invoke(Context{}),
+	)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("invalid name should panic")
+		}
+	}()
+	RegisterMacro("in valid", func(args ...string) Action { return ActionValues() }, "")
+}
+
+func TestMacroCommand(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "macro-test"}
+	Gen(rootCmd)
+
+	execute := func(args ...string) string {
+		var stdout bytes.Buffer
+		rootCmd.SetOut(&stdout)
+		rootCmd.SetArgs(append([]string{"_carapace", "macro"}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		return stdout.String()
+	}
+
+	if list := execute(); !strings.Contains(list, "test.args\targuments and words\n") {
+		t.Errorf("missing macro in list: %#v", list)
+	}
+
+	expected := ActionValues("one", "word").Invoke(NewContext("word", "")).value("export", "")
+	if actual := strings.TrimSpace(execute("test.args", "one", "--", "word", "")); actual != expected {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+
+	usage := "macro [name] [args] -- [words]"
+	a, context := traverse(rootCmd, []string{"_carapace", "macro", "test.args", "one", "--", "word", ""})
+	assertEqual(t, ActionValues("one", "word").Usage(usage).Invoke(Context{}), a.Invoke(context))
+
+	a, context = traverse(rootCmd, []string{"_carapace", "macro", "test.args", ""})
+	assertEqual(t, ActionValues("--").Usage(usage).Invoke(Context{}), a.Invoke(context))
+}
@@ -0,0 +1,263 @@
+package carapace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/log"
+	"github.com/carapace-sh/carapace/internal/spec"
+	"github.com/carapace-sh/carapace/internal/uid"
+	"github.com/carapace-sh/carapace/pkg/xdg"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// macroOriginal references the completion of the command in an overlay.
+const macroOriginal = "$original"
+
+// loadOverlay loads the overlay for the current executable (`carapace/overlays/<executable>.yaml`).
+// It returns nil if no overlay exists.
+func loadOverlay() (*spec.Command, error) {
+	dir, err := xdg.UserConfigDir()
+	if err != nil {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "carapace", "overlays", uid.Executable()+".yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var o spec.Command
+	if err := yaml.Unmarshal(content, &o); err != nil {
+		return nil, fmt.Errorf("invalid overlay: %v", err.Error())
+	}
+	return &o, nil
+}
+
+// applyOverlay merges the overlay for given root command into storage.
+// Invalid entries are skipped.
+func applyOverlay(cmd *cobra.Command) error {
+	o, err := loadOverlay()
+	if err != nil || o == nil {
+		return err
+	}
+
+	for _, e := range overlay(cmd.Root(), *o, true) {
+		log.Logger.Warn("skipping overlay entry", "error", e)
+	}
+	return nil
+}
+
+// checkOverlays validates the overlay against the command trees in storage matching its name.
+func checkOverlays() []string {
+	o, err := loadOverlay()
+	if err != nil {
+		return []string{fmt.Sprintf("overlay for %v: %v", uid.Executable(), err.Error())}
+	}
+	if o == nil {
+		return nil
+	}
+
+	roots := make(map[*cobra.Command]bool)
+	storageMutex.RLock()
+	for cmd := range storage {
+		if root := cmd.Root(); o.Name == "" || root.Name() == o.Name {
+			roots[root] = true
+		}
+	}
+	storageMutex.RUnlock()
+
+	errors := make([]string, 0)
+	for root := range roots {
+		for _, e := range overlay(root, *o, false) {
+			errors = append(errors, fmt.Sprintf("overlay for %v: %v", root.Name(), e))
+		}
+	}
+	return errors
+}
+
+// isConfigSandboxed checks if the config directory is located in the temporary directory (e.g. `t.TempDir()`).
+func isConfigSandboxed() bool {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	return dir != "" && strings.HasPrefix(filepath.Clean(dir), filepath.Clean(os.TempDir())+string(filepath.Separator))
+}
+
+// overlay validates given overlay and applies it to the command if apply is true.
+func overlay(cmd *cobra.Command, o spec.Command, apply bool) []string {
+	errors := make([]string, 0)
+	addError := func(format string, args ...interface{}) {
+		errors = append(errors, fmt.Sprintf("%v: ", uid.Command(cmd))+fmt.Sprintf(format, args...))
+	}
+
+	if o.Hidden && apply {
+		cmd.Hidden = true
+	}
+
+	for _, f := range []struct {
+		flags      map[string]string
+		persistent bool
+	}{
+		{o.Flags, false},
+		{o.PersistentFlags, true},
+	} {
+		for definition, usage := range f.flags {
+			d, err := spec.ParseDefinition(definition)
+			if err != nil {
+				addError(err.Error())
+				continue
+			}
+
+			if existing := cmd.Flag(d.Name); existing != nil {
+				if d.Shorthand != "" && existing.Shorthand != d.Shorthand {
+					addError("shorthand mismatch for flag %v", d.Name)
+				}
+				continue // already defined
+			}
+
+			if d.Shorthand != "" && (cmd.Flags().ShorthandLookup(d.Shorthand) != nil || cmd.InheritedFlags().ShorthandLookup(d.Shorthand) != nil) {
+				addError("shorthand already in use: %v", definition)
+				continue
+			}
+
+			if apply {
+				fs := cmd.Flags()
+				if f.persistent {
+					fs = cmd.PersistentFlags()
+				}
+				if err := spec.AddFlag(fs, definition, usage); err != nil {
+					addError(err.Error())
+				}
+			}
+		}
+	}
+
+	entry := storage.get(cmd)
+	for name, values := range o.Completion.Flag {
+		if cmd.LocalFlags().Lookup(name) == nil && !overlayDefinesFlag(o, name) {
+			addError("unknown flag: %v", name)
+			continue
+		}
+		if e := checkOverlaySpec(values); len(e) > 0 {
+			addError("flag %v: %v", name, e[0])
+			continue
+		}
+
+		if apply {
+			entry.flagMutex.RLock()
+			original := entry.flag[name]
+			entry.flagMutex.RUnlock()
+			Carapace{cmd}.FlagCompletion(ActionMap{name: overlayAction(original, values)})
+		}
+	}
+
+	if positional, ok := overlayActions(addError, "positional", entry.positional, o.Completion.Positional); ok && apply {
+		Carapace{cmd}.PositionalCompletion(positional...)
+	}
+	if positionalAny, ok := overlayActions(addError, "positionalany", actionSlice(entry.positionalAny), nonEmpty(o.Completion.PositionalAny)); ok && apply {
+		Carapace{cmd}.PositionalAnyCompletion(positionalAny[0])
+	}
+	if dash, ok := overlayActions(addError, "dash", entry.dash, o.Completion.Dash); ok && apply {
+		Carapace{cmd}.DashCompletion(dash...)
+	}
+	if dashAny, ok := overlayActions(addError, "dashany", actionSlice(entry.dashAny), nonEmpty(o.Completion.DashAny)); ok && apply {
+		Carapace{cmd}.DashAnyCompletion(dashAny[0])
+	}
+
+	for _, subOverlay := range o.Commands {
+		name := ""
+		if fields := strings.Fields(subOverlay.Name); len(fields) > 0 {
+			name = fields[0]
+		}
+
+		if subCmd := subcommand(cmd, name); subCmd != nil {
+			errors = append(errors, overlay(subCmd, subOverlay, apply)...)
+		} else {
+			addError("unknown command: %v", name)
+		}
+	}
+	return errors
+}
+
+// overlayActions merges the overlay completions with the original ones (empty lists keep the original).
+func overlayActions(addError func(string, ...interface{}), name string, original []Action, values [][]string) ([]Action, bool) {
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	actions := make([]Action, len(original))
+	copy(actions, original)
+	for index, v := range values {
+		if e := checkOverlaySpec(v); len(e) > 0 {
+			addError("%v %v: %v", name, index+1, e[0])
+			return nil, false
+		}
+
+		for len(actions) <= index {
+			actions = append(actions, ActionValues())
+		}
+		if len(v) > 0 {
+			actions[index] = overlayAction(actions[index], v)
+		}
+	}
+	return actions, true
+}
+
+// overlayAction replaces the original completion or extends it if values contain `$original`.
+func overlayAction(original Action, values []string) Action {
+	filtered := make([]string, 0, len(values))
+	extend := false
+	for _, value := range values {
+		if value == macroOriginal {
+			extend = true
+		} else {
+			filtered = append(filtered, value)
+		}
+	}
+
+	if extend {
+		return Batch(original, ActionSpec(filtered...)).ToA()
+	}
+	return ActionSpec(filtered...)
+}
+
+// checkOverlaySpec is like checkSpec but allows `$original`.
+func checkOverlaySpec(values []string) []string {
+	filtered := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != macroOriginal {
+			filtered = append(filtered, value)
+		}
+	}
+	return checkSpec(filtered)
+}
+
+func overlayDefinesFlag(o spec.Command, name string) bool {
+	for _, flags := range []map[string]string{o.Flags, o.PersistentFlags} {
+		for definition := range flags {
+			if d, err := spec.ParseDefinition(definition); err == nil && d.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nonEmpty(values []string) [][]string {
+	if len(values) == 0 {
+		return nil
+	}
+	return [][]string{values}
+}
+
+func actionSlice(a *Action) []Action {
+	if a == nil {
+		return nil
+	}
+	return []Action{*a}
+}
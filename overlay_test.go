@@ -0,0 +1,133 @@
+package carapace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carapace-sh/carapace/internal/uid"
+	"github.com/spf13/cobra"
+)
+
+func writeOverlay(t *testing.T, content string) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "carapace", "overlays"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "carapace", "overlays", uid.Executable()+".yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", dir)
+}
+
+func TestOverlay(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "overlay-test"}
+	rootCmd.Flags().String("replace", "", "")
+	rootCmd.Flags().String("extend", "", "")
+	subCmd := &cobra.Command{Use: "sub", Run: func(cmd *cobra.Command, args []string) {}}
+	rootCmd.AddCommand(subCmd)
+
+	Gen(rootCmd).FlagCompletion(ActionMap{
+		"replace": ActionValues("original"),
+		"extend":  ActionValues("original"),
+	})
+	Gen(subCmd).PositionalCompletion(
+		ActionValues("first"),
+		ActionValues("second"),
+	)
+
+	writeOverlay(t, `
+name: overlay-test
+flags:
+  -a, --added=: added flag
+completion:
+  flag:
+    replace: ["replaced"]
+    extend: ["$original", "extended"]
+    added: ["one", "two"]
+commands:
+  - name: sub
+    hidden: true
+    completion:
+      positional:
+        - []
+        - ["$(echo third)"]
+`)
+
+	if err := applyOverlay(rootCmd); err != nil {
+		t.Fatal(err)
+	}
+
+	if !subCmd.Hidden {
+		t.Error("sub should be hidden")
+	}
+	if rootCmd.Flag("added") == nil {
+		t.Fatal("flag added should exist")
+	}
+
+	complete := func(cmd *cobra.Command, args ...string) InvokedAction {
+		a, context := traverse(cmd, args)
+		return a.Invoke(context)
+	}
+
+	assertEqual(t, ActionValues("replaced").Invoke(Context{}).Prefix("--replace="), complete(rootCmd, "--replace="))
+	assertEqual(t, ActionValues("original", "extended").Invoke(Context{}).Prefix("--extend="), complete(rootCmd, "--extend="))
+	assertEqual(t, ActionValues("one", "two").Usage("added flag").Invoke(Context{}).Prefix("--added="), complete(rootCmd, "--added="))
+	assertEqual(t, ActionValues("first").Invoke(Context{}), complete(rootCmd, "sub", ""))
+	assertEqual(t, ActionValues("third").Invoke(Context{}), complete(rootCmd, "sub", "x", ""))
+}
+
+func TestCheckOverlays(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "overlay-check"}
+	rootCmd.Flags().String("flag", "", "")
+	Gen(rootCmd).FlagCompletion(ActionMap{"flag": ActionValues("original")})
+
+	writeOverlay(t, `
+name: overlay-check
+completion:
+  flag:
+    unknown: ["value"]
+    flag: ["$unknown"]
+commands:
+  - name: missing
+`)
+
+	errors := checkOverlays()
+	for _, expected := range []string{
+		"unknown flag: unknown",
+		"flag flag: unknown macro: unknown",
+		"unknown command: missing",
+	} {
+		found := false
+		for _, e := range errors {
+			if strings.HasPrefix(e, "overlay for overlay-check: ") && strings.HasSuffix(e, expected) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing error %#v in %#v", expected, errors)
+		}
+	}
+
+	if rootCmd.Flag("flag") == nil {
+		t.Error("flag should still exist")
+	}
+}
+
+func TestIsConfigSandboxed(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if isConfigSandboxed() {
+		t.Error("unset config dir should not be sandboxed")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "/home/user/.config")
+	if isConfigSandboxed() {
+		t.Error("user config dir should not be sandboxed")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if !isConfigSandboxed() {
+		t.Error("temporary config dir should be sandboxed")
+	}
+}
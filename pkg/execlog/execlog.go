@@ -1,6 +1,10 @@
 package execlog
 
 import (
+	"errors"
+	"os/exec"
+	"time"
+
 	shlex "github.com/carapace-sh/carapace-shlex"
 	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/carapace-sh/carapace/third_party/golang.org/x/sys/execabs"
@@ -8,36 +12,64 @@ import (
 
 type Cmd struct {
 	*execabs.Cmd
+	start time.Time
 }
 
-// Command is like execabs.Command but logs args on execution.
+// Command is like execabs.Command but logs args, duration and exit code on execution.
 func Command(name string, arg ...string) *Cmd {
 	cmd := &Cmd{
-		execabs.Command(name, arg...),
+		Cmd: execabs.Command(name, arg...),
 	}
 	return cmd
 }
 
 func (c *Cmd) CombinedOutput() ([]byte, error) {
-	log.LOG.Printf("executing %#v", shlex.Join(c.Args))
-	return c.Cmd.CombinedOutput()
+	start := time.Now()
+	output, err := c.Cmd.CombinedOutput()
+	c.log(start, err)
+	return output, err
 }
 
 func (c *Cmd) Output() ([]byte, error) {
-	log.LOG.Printf("executing %#v", shlex.Join(c.Args))
-	return c.Cmd.Output()
+	start := time.Now()
+	output, err := c.Cmd.Output()
+	c.log(start, err)
+	return output, err
 }
 
 func (c *Cmd) Run() error {
-	log.LOG.Printf("executing %#v", shlex.Join(c.Args))
-	return c.Cmd.Run()
+	start := time.Now()
+	err := c.Cmd.Run()
+	c.log(start, err)
+	return err
 }
 
 func (c *Cmd) Start() error {
-	log.LOG.Printf("executing %#v", shlex.Join(c.Args))
+	c.start = time.Now()
+	log.Logger.Debug("starting command", "command", shlex.Join(c.Args))
 	return c.Cmd.Start()
 }
 
+func (c *Cmd) Wait() error {
+	err := c.Cmd.Wait()
+	c.log(c.start, err)
+	return err
+}
+
+func (c *Cmd) log(start time.Time, err error) {
+	exitCode := 0
+	if c.ProcessState != nil {
+		exitCode = c.ProcessState.ExitCode()
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		log.Logger.Error("failed to execute command", "command", shlex.Join(c.Args), "duration", time.Since(start), "error", err.Error())
+		return
+	}
+	log.Logger.Info("executed command", "command", shlex.Join(c.Args), "duration", time.Since(start), "exit", exitCode)
+}
+
 // Command is the same as execabs.Command.
 func LookPath(file string) (string, error) {
 	return execabs.LookPath(file)
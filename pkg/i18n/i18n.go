@@ -0,0 +1,83 @@
+// Package i18n provides message catalogs to localize descriptions, usage and messages.
+package i18n
+
+import (
+	"strings"
+	"sync"
+)
+
+// Catalog maps original strings to their translation.
+type Catalog map[string]string
+
+var catalogs = struct {
+	sync.RWMutex
+	entries map[string]Catalog
+}{
+	entries: make(map[string]Catalog),
+}
+
+// Register registers a catalog for given language (`de`, `de_DE`).
+// Entries are merged with previously registered ones.
+//
+//	i18n.Register("de", i18n.Catalog{
+//		"show all files": "alle Dateien anzeigen",
+//	})
+func Register(lang string, c Catalog) {
+	lang = normalize(lang)
+
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	if _, ok := catalogs.entries[lang]; !ok {
+		catalogs.entries[lang] = make(Catalog)
+	}
+	for key, value := range c {
+		catalogs.entries[lang][key] = value
+	}
+}
+
+// Language returns the language set by the environment (LC_ALL > LC_MESSAGES > LANG).
+//
+//	i18n.Language(c.Getenv) // de_DE
+func Language(getenv func(key string) string) string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := getenv(key); value != "" {
+			return normalize(value)
+		}
+	}
+	return ""
+}
+
+// normalize strips encoding and modifier (`de_DE.UTF-8@euro` -> `de_DE`).
+func normalize(lang string) string {
+	if index := strings.IndexAny(lang, ".@"); index != -1 {
+		lang = lang[:index]
+	}
+	switch lang {
+	case "C", "POSIX":
+		return ""
+	}
+	return strings.Replace(lang, "-", "_", 1)
+}
+
+// Translate returns the translation of given string (`de_DE` > `de`).
+// Falls back to the original string if there is none.
+func Translate(lang, s string) string {
+	if lang == "" || s == "" {
+		return s
+	}
+
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	candidates := []string{lang}
+	if index := strings.Index(lang, "_"); index != -1 {
+		candidates = append(candidates, lang[:index])
+	}
+	for _, candidate := range candidates {
+		if translated, ok := catalogs.entries[candidate][s]; ok {
+			return translated
+		}
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package i18n
+
+import "testing"
+
+func TestLanguage(t *testing.T) {
+	_test := func(expected string, env map[string]string) {
+		if actual := Language(func(key string) string { return env[key] }); actual != expected {
+			t.Errorf("expected '%v' for %v [was: '%v']", expected, env, actual)
+		}
+	}
+
+	_test("", map[string]string{})
+	_test("", map[string]string{"LANG": "C.UTF-8"})
+	_test("", map[string]string{"LANG": "POSIX"})
+	_test("de_DE", map[string]string{"LANG": "de_DE.UTF-8@euro"})
+	_test("fr", map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "fr"})
+	_test("en_US", map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "fr", "LC_ALL": "en-US"})
+}
+
+func TestTranslate(t *testing.T) {
+	Register("xx", Catalog{"one": "eins", "two": "zwei"})
+	Register("xx_YY", Catalog{"two": "zwo"})
+
+	_test := func(lang, s, expected string) {
+		if actual := Translate(lang, s); actual != expected {
+			t.Errorf("expected '%v' for '%v' in '%v' [was: '%v']", expected, s, lang, actual)
+		}
+	}
+
+	_test("", "one", "one")
+	_test("xx", "one", "eins")
+	_test("xx", "two", "zwei")
+	_test("xx_YY", "one", "eins")
+	_test("xx_YY", "two", "zwo")
+	_test("xx_ZZ", "two", "zwei")
+	_test("xx", "three", "three")
+	_test("zz", "one", "one")
+}
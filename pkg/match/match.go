@@ -1,9 +1,9 @@
 package match
 
 import (
-	"os"
-	"strconv"
 	"strings"
+
+	"github.com/carapace-sh/carapace/pkg/settings"
 )
 
 type Match int
@@ -35,23 +35,24 @@ func (m Match) TrimPrefix(s, prefix string) string {
 	return s
 }
 
-var match = CASE_SENSITIVE
-
-func init() {
-	switch os.Getenv("CARAPACE_MATCH") {
-	case "CASE_INSENSITIVE", strconv.Itoa(int(CASE_INSENSITIVE)):
-		match = CASE_INSENSITIVE
+// current returns the match mode configured in settings (`carapace.Match`).
+func current() Match {
+	switch settings.Carapace.Match {
+	case "CASE_INSENSITIVE":
+		return CASE_INSENSITIVE
+	default:
+		return CASE_SENSITIVE
 	}
 }
 
 func Equal(s, t string) bool {
-	return match.Equal(s, t)
+	return current().Equal(s, t)
 }
 
 func HasPrefix(s, prefix string) bool {
-	return match.HasPrefix(s, prefix)
+	return current().HasPrefix(s, prefix)
 }
 
 func TrimPrefix(s, prefix string) string {
-	return match.TrimPrefix(s, prefix)
+	return current().TrimPrefix(s, prefix)
 }
@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package ps
+
+// arguments returns the command line of given process (not supported on this platform).
+func arguments(pid int) []string {
+	return nil
+}
@@ -2,58 +2,151 @@
 package ps
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/carapace-sh/carapace/internal/env"
 	"github.com/carapace-sh/carapace/third_party/github.com/mitchellh/go-ps"
 )
 
+const maxDepth = 16 // maximum amount of parent processes to walk
+
 // DetermineShell determines shell by parent process name.
+//
+// It can be overridden with CARAPACE_SHELL and falls back to shell specific
+// environment variables (e.g. ZSH_VERSION) if no known shell was found.
 func DetermineShell() string {
+	shell, _ := DetermineShellStrategy()
+	return shell
+}
+
+// DetermineShellStrategy determines the shell along with a description of the strategy that decided it.
+func DetermineShellStrategy() (shell string, strategy string) {
+	if shell := env.Shell(); shell != "" {
+		return shell, fmt.Sprintf("environment variable %v", env.CARAPACE_SHELL)
+	}
+
+	if shell, executable := determineByProcess(); shell != "" {
+		return shell, fmt.Sprintf("parent process %#v", executable)
+	}
+
+	if shell, variable := determineByHint(); shell != "" {
+		return shell, fmt.Sprintf("environment variable %v", variable)
+	}
+	return "", "none"
+}
+
+func determineByProcess() (shell string, executable string) {
 	process, err := ps.FindProcess(os.Getpid())
-	if err != nil {
-		return ""
+	if err != nil || process == nil {
+		return "", ""
 	}
-	for {
+
+	for depth := 0; depth < maxDepth; depth++ {
+		if process.PPid() == process.Pid() {
+			return "", ""
+		}
 		if process, err = ps.FindProcess(process.PPid()); err != nil || process == nil {
-			return ""
+			return "", ""
 		}
 
 		executable := process.Executable()
-		switch strings.SplitN(strings.TrimSuffix(executable, ".exe"), "-", 2)[0] {
-		case "bash":
-			if isBLE() {
-				return "bash-ble"
+		if shell := shellFor(executable); shell != "" {
+			return shell, executable
+		}
+
+		if isInterpreter(executable) { // e.g. `pwsh` launched via `dotnet`
+			for _, arg := range arguments(process.Pid()) {
+				if shell := shellFor(strings.TrimSuffix(arg, ".dll")); shell != "" {
+					return shell, executable
+				}
 			}
-			return "bash"
-		case "elvish":
-			return "elvish"
-		case "fish":
-			return "fish"
-		case "ion":
-			return "ion"
-		case "nu":
-			return "nushell"
-		case "oil":
-			return "oil"
-		case "osh":
-			return "oil"
-		case "powershell":
-			return "powershell"
-		case "pwsh":
-			return "powershell"
-		case "tcsh":
-			return "tcsh"
-		case "xonsh":
+		}
+		// anything else (e.g. `script`, `sudo`, `direnv`, `nix`) is a possible wrapper so keep walking
+	}
+	return "", ""
+}
+
+// shellFor maps given executable to the shell name.
+func shellFor(executable string) string {
+	name := filepath.Base(executable)
+	name = strings.TrimSuffix(name, ".exe")
+	name = strings.TrimPrefix(name, "-") // login shell
+
+	if strings.HasPrefix(name, ".") && strings.HasSuffix(name, "-wrapped") { // nix packaged version
+		name = strings.TrimSuffix(strings.TrimPrefix(name, "."), "-wrapped")
+	}
+
+	switch strings.SplitN(name, "-", 2)[0] {
+	case "bash":
+		if isBLE() {
+			return "bash-ble"
+		}
+		return "bash"
+	case "elvish":
+		return "elvish"
+	case "fish":
+		return "fish"
+	case "ion":
+		return "ion"
+	case "ksh", "ksh93", "mksh", "lksh", "pdksh":
+		return "ksh"
+	case "murex":
+		return "murex"
+	case "nu":
+		return "nushell"
+	case "oil":
+		return "oil"
+	case "osh":
+		return "oil"
+	case "powershell":
+		return "powershell"
+	case "pwsh":
+		return "powershell"
+	case "tcsh":
+		return "tcsh"
+	case "xonsh":
+		return "xonsh"
+	case "yash":
+		return "yash"
+	case "zsh":
+		return "zsh"
+	default:
+		if strings.Contains(executable, "xonsh-wrapped") { // nix packaged version
 			return "xonsh"
-		case "zsh":
-			return "zsh"
-		default:
-			if strings.Contains(executable, "xonsh-wrapped") { // nix packaged version
-				return "xonsh"
+		}
+		return ""
+	}
+}
+
+// isInterpreter checks if the shell might be hidden in the arguments of the executable.
+func isInterpreter(executable string) bool {
+	name := strings.TrimSuffix(filepath.Base(executable), ".exe")
+	return name == "dotnet" || strings.HasPrefix(name, "python")
+}
+
+func determineByHint() (shell string, variable string) {
+	hints := []struct {
+		variable string
+		shell    string
+	}{
+		{"NU_VERSION", "nushell"},
+		{"FISH_VERSION", "fish"},
+		{"ZSH_VERSION", "zsh"},
+		{"BASH_VERSION", "bash"},
+	}
+
+	for _, hint := range hints {
+		if _, ok := os.LookupEnv(hint.variable); ok {
+			if hint.shell == "bash" && isBLE() {
+				return "bash-ble", hint.variable
 			}
+			return hint.shell, hint.variable
 		}
 	}
+	return "", ""
 }
 
 func isBLE() bool {
@@ -0,0 +1,58 @@
+package ps
+
+import (
+	"os"
+	"testing"
+)
+
+func TestShellFor(t *testing.T) {
+	for executable, expected := range map[string]string{
+		"zsh":                 "zsh",
+		"-zsh":                "zsh",
+		"/usr/bin/fish":       "fish",
+		".fish-wrapped":       "fish",
+		"pwsh.exe":            "powershell",
+		".xonsh-wrapped":      "xonsh",
+		"nu":                  "nushell",
+		"murex":               "murex",
+		"ksh93":               "ksh",
+		"mksh":                "ksh",
+		"yash":                "yash",
+		"sudo":                "",
+		"script":              "",
+		"pwsh.dll":            "",
+		"python3.11":          "",
+		"nix-shell":           "",
+		"direnv":              "",
+		"/nix/store/bash-5.2": "bash",
+	} {
+		if actual := shellFor(executable); actual != expected {
+			t.Errorf("expected %#v for %#v [was: %#v]", expected, executable, actual)
+		}
+	}
+}
+
+func TestDetermineShellOverride(t *testing.T) {
+	os.Setenv("CARAPACE_SHELL", "elvish")
+	defer os.Unsetenv("CARAPACE_SHELL")
+
+	if shell, strategy := DetermineShellStrategy(); shell != "elvish" || strategy != "environment variable CARAPACE_SHELL" {
+		t.Errorf("unexpected shell %#v by %v", shell, strategy)
+	}
+}
+
+func TestDetermineByHint(t *testing.T) {
+	for _, variable := range []string{"NU_VERSION", "FISH_VERSION", "ZSH_VERSION", "BASH_VERSION"} {
+		if value, ok := os.LookupEnv(variable); ok {
+			defer os.Setenv(variable, value)
+			os.Unsetenv(variable)
+		}
+	}
+
+	os.Setenv("ZSH_VERSION", "5.9")
+	defer os.Unsetenv("ZSH_VERSION")
+
+	if shell, variable := determineByHint(); shell != "zsh" || variable != "ZSH_VERSION" {
+		t.Errorf("unexpected shell %#v by %v", shell, variable)
+	}
+}
@@ -0,0 +1,267 @@
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/carapace-sh/carapace/pkg/style"
+)
+
+// writeStub writes an executable script to a temporary directory prepended to PATH.
+func writeStub(t *testing.T, name, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0755); err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestBridgeBash(t *testing.T) {
+	Action(t, func() carapace.Action {
+		return carapace.ActionBridgeBash("fake", "fake.bash")
+	})(func(s *Sandbox) {
+		s.Files(
+			"fake.bash", `
+_fake() {
+  local cur="${COMP_WORDS[COMP_CWORD]}"
+  case "${COMP_WORDS[COMP_CWORD-1]}" in
+  dir)
+    compopt -o filenames
+    COMPREPLY=($(compgen -d -- "${cur}"))
+    ;;
+  nospace)
+    compopt -o nospace
+    COMPREPLY=($(compgen -W "key=" -- "${cur}"))
+    ;;
+  =)
+    COMPREPLY=($(compgen -W "json yaml" -- "${cur}"))
+    ;;
+  json)
+    COMPREPLY=($(compgen -W "compact pretty" -- "${cur}"))
+    ;;
+  *)
+    COMPREPLY=($(compgen -W "alpha beta dir nospace" -- "${cur}"))
+    ;;
+  esac
+}
+complete -F _fake fake
+`,
+			"dirA/file.txt", "",
+		)
+
+		s.Run("").
+			Expect(carapace.ActionValues("alpha", "beta", "dir", "nospace"))
+
+		s.Run("a").
+			Expect(carapace.ActionValues("alpha"))
+
+		s.Run("--format=").
+			Expect(carapace.ActionValues("json", "yaml").
+				Prefix("--format="))
+
+		s.Run("--format=json", "").
+			Expect(carapace.ActionValues("compact", "pretty"))
+
+		s.Run("--format=json", "--mode=").
+			Expect(carapace.ActionValues("json", "yaml").
+				Prefix("--mode="))
+
+		s.Run("dir", "").
+			Expect(carapace.ActionValues("dirA/").
+				StyleF(style.ForPath).
+				NoSpace('/'))
+
+		s.Run("nospace", "").
+			Expect(carapace.ActionValues("key=").
+				NoSpace())
+	})
+}
+
+func TestBridgeBashLine(t *testing.T) {
+	Action(t, func() carapace.Action {
+		return carapace.ActionBridgeBash("fake", "fake.bash")
+	})(func(s *Sandbox) {
+		s.Files(
+			"fake.bash", `
+_fake() {
+  local line="${COMP_LINE:0:COMP_POINT}"
+  case "${line##* }" in
+  --format=*)
+    COMPREPLY=($(compgen -W "json yaml" -- "${COMP_WORDS[COMP_CWORD]}"))
+    ;;
+  host:*)
+    COMPREPLY=($(compgen -W "/tmp /var" -- "${COMP_WORDS[COMP_CWORD]}"))
+    ;;
+  *)
+    COMPREPLY=($(compgen -W "--format= host:" -- "${COMP_WORDS[COMP_CWORD]}"))
+    ;;
+  esac
+}
+complete -F _fake fake
+`,
+		)
+
+		s.Run("--format=").
+			Expect(carapace.ActionValues("json", "yaml").
+				Prefix("--format="))
+
+		s.Run("--verbose", "host:").
+			Expect(carapace.ActionValues("/tmp", "/var").
+				Prefix("host:"))
+	})
+}
+
+func TestBridgeZsh(t *testing.T) {
+	if _, err := exec.LookPath("zsh"); err != nil {
+		t.Skip("zsh not installed")
+	}
+
+	Action(t, func() carapace.Action {
+		return carapace.ActionBridgeZsh("fake")
+	})(func(s *Sandbox) {
+		s.Files(
+			"_fake", `#compdef fake
+local -a modes=('alpha:first mode' 'beta:second mode')
+_describe -t modes 'mode' modes
+`,
+		)
+		s.Env("FPATH", s.mock.WorkDir())
+
+		s.Run("").
+			Expect(carapace.ActionValuesDescribed(
+				"alpha", "first mode",
+				"beta", "second mode",
+			).Tag("modes").
+				NoSpace('/', '='))
+	})
+}
+
+func TestBridgeCobra(t *testing.T) {
+	writeStub(t, "fake-cobra", `#!/bin/sh
+case "$*" in
+"__complete repo order ") printf 'zeta\tlast\nalpha\tfirst\n:36\n' ;;
+"__complete repo nospace ") printf 'key=\n:6\n' ;;
+"__complete repo ") printf 'order\tkeep order\nnospace\n:4\n' ;;
+*) echo :1 ;;
+esac
+`)
+
+	Action(t, func() carapace.Action {
+		return carapace.ActionBridgeCobra("fake-cobra", "repo")
+	})(func(s *Sandbox) {
+		s.Run("").
+			Expect(carapace.ActionValuesDescribed(
+				"nospace", "",
+				"order", "keep order",
+			))
+
+		s.Run("order", "").
+			Expect(carapace.ActionValuesDescribed(
+				"zeta", "last",
+				"alpha", "first",
+			).KeepOrder())
+
+		s.Run("nospace", "").
+			Expect(carapace.ActionValues("key=").
+				NoSpace())
+
+		s.Run("unknown", "").
+			Expect(carapace.ActionMessage("an error occurred"))
+	})
+}
+
+func TestBridgeFish(t *testing.T) {
+	writeStub(t, "fish", "#!/bin/sh\nprintf 'dirA/\\nfile.txt\\tfile\\n--flag\\tsome flag\\n'\n")
+
+	Action(t, func() carapace.Action {
+		return carapace.ActionBridgeFish("fake")
+	})(func(s *Sandbox) {
+		s.Files(
+			"dirA/file.txt", "",
+			"file.txt", "",
+		)
+
+		s.Run("").
+			Expect(carapace.Batch(
+				carapace.ActionValuesDescribed(
+					"dirA/", "",
+					"file.txt", "file",
+				).StyleF(style.ForPath),
+				carapace.ActionValuesDescribed(
+					"--flag", "some flag",
+				),
+			).ToA().
+				NoSpace('/'))
+	})
+}
+
+func TestBridgeFishMissing(t *testing.T) {
+	if _, err := exec.LookPath("fish"); err == nil {
+		t.Skip("fish is installed")
+	}
+
+	Action(t, func() carapace.Action {
+		return carapace.ActionBridgeFish("fake")
+	})(func(s *Sandbox) {
+		s.Run("").
+			Expect(carapace.ActionMessage("fish not found in PATH"))
+	})
+}
+
+func TestBridgeArgcomplete(t *testing.T) {
+	writeStub(t, "fake-argcomplete", `#!/bin/sh
+[ "$_ARGCOMPLETE" = 1 ] || exit 1
+echo "should be ignored"
+case "$COMP_LINE" in
+"fake-argcomplete sub "*) printf 'key=\nvalue \tsome value' >&8 ;;
+*) printf 'sub\tsubcommand\n--flag\tsome flag\ncursor\t%s' "$COMP_POINT" >&8 ;;
+esac
+`)
+
+	Action(t, func() carapace.Action {
+		return carapace.ActionBridgeArgcomplete("fake-argcomplete")
+	})(func(s *Sandbox) {
+		s.Run("").
+			Expect(carapace.ActionValuesDescribed(
+				"sub", "subcommand",
+				"--flag", "some flag",
+				"cursor", "17",
+			).NoSpace('/', '='))
+
+		s.Run("sub", "").
+			Expect(carapace.ActionValuesDescribed(
+				"key=", "",
+				"value", "some value",
+			).NoSpace('/', '='))
+	})
+}
+
+func TestBridgeClick(t *testing.T) {
+	writeStub(t, "fake-click", `#!/bin/sh
+[ "$_FAKE_CLICK_COMPLETE" = zsh_complete ] || exit 1
+case "$COMP_CWORD" in
+1) printf 'plain\nsub\nsubcommand\nplain\nhost\:port\naddress\nplain\n--flag\n_\n' ;;
+*) printf 'dir\n%s\n_\n' "$COMP_WORDS" ;;
+esac
+`)
+
+	Action(t, func() carapace.Action {
+		return carapace.ActionBridgeClick("fake-click")
+	})(func(s *Sandbox) {
+		s.Files("dirA/file.txt", "")
+
+		s.Run("").
+			Expect(carapace.ActionValuesDescribed(
+				"sub", "subcommand",
+				"host:port", "address",
+				"--flag", "",
+			))
+
+		s.Run("sub", "").
+			Expect(carapace.ActionDirectories())
+	})
+}
@@ -0,0 +1,62 @@
+// Package settings provides configurable behaviour backed by `carapace/settings.json`
+package settings
+
+import (
+	"time"
+
+	"github.com/carapace-sh/carapace/internal/config"
+)
+
+// Register a settings configuration.
+// Environment variables (`env` tag) are applied on registration,
+// values of the config file once it is loaded during completion.
+// Supported field types are bool, int, string and time.Duration.
+//
+//	var Example = struct {
+//		Verbose bool          `description:"verbose output" env:"EXAMPLE_VERBOSE"`
+//		Timeout time.Duration `description:"request timeout"`
+//	}{
+//		Timeout: 5 * time.Second,
+//	}
+//
+//	func init() {
+//		Register("example", &Example)
+//	}
+func Register(name string, i interface{}) { config.RegisterSetting(name, i) }
+
+// Get a setting
+//
+//	Get("carapace.Hidden")
+func Get(key string) (string, error) { return config.GetSetting(key) }
+
+// Set a setting (an empty value restores the default)
+//
+//	Set("carapace.Hidden", "true")
+func Set(key, value string) error { return config.SetSetting(key, value) }
+
+type carapace struct {
+	Match            string        `description:"match mode" env:"CARAPACE_MATCH" values:"CASE_SENSITIVE,CASE_INSENSITIVE" legacy:"CASE_INSENSITIVE" tag:"completion settings"`
+	Hidden           bool          `description:"show hidden commands and flags" env:"CARAPACE_HIDDEN" tag:"completion settings"`
+	Lenient          bool          `description:"allow unknown flags" env:"CARAPACE_LENIENT" tag:"completion settings"`
+	DescriptionWidth int           `description:"maximum width of descriptions (0 derives it from the terminal)" env:"CARAPACE_DESCRIPTION" tag:"completion settings"`
+	Timeout          time.Duration `description:"maximum duration of a completion (0 disables it)" env:"CARAPACE_TIMEOUT" tag:"completion settings"`
+	CacheBudget      int           `description:"maximum size of the cache in megabytes (0 disables it)" env:"CARAPACE_CACHE_BUDGET" tag:"cache settings"`
+	Log              string        `description:"log level" env:"CARAPACE_LOG" values:"off,debug,info" legacy:"debug" tag:"log settings"`
+	LogFormat        string        `description:"log format" env:"CARAPACE_LOG_FORMAT" values:"text,json" tag:"log settings"`
+	LogSize          int           `description:"maximum size of the log file in megabytes before it is rotated (0 disables it)" env:"CARAPACE_LOG_SIZE" tag:"log settings"`
+	Theme            string        `description:"style theme" env:"CARAPACE_THEME" tag:"style settings"`
+	ColorDepth       string        `description:"color depth of the terminal" env:"CARAPACE_COLOR_DEPTH" values:"auto,16,256,truecolor" tag:"style settings"`
+}
+
+var Carapace = carapace{
+	Match:      "CASE_SENSITIVE",
+	Log:        "off",
+	LogFormat:  "text",
+	LogSize:    10,
+	Theme:      "default",
+	ColorDepth: "auto",
+}
+
+func init() {
+	Register("carapace", &Carapace)
+}
@@ -0,0 +1,127 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carapace-sh/carapace/internal/config"
+)
+
+func TestRegister(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "carapace"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"test": {"Enabled": true, "Count": 3, "Timeout": "2s", "Name": "file"}}`
+	if err := os.WriteFile(filepath.Join(dir, "carapace", "settings.json"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := os.Getenv("XDG_CONFIG_HOME")
+	defer os.Setenv("XDG_CONFIG_HOME", previous)
+	os.Setenv("XDG_CONFIG_HOME", dir)
+
+	defer os.Unsetenv("CARAPACE_TEST_NAME")
+	os.Setenv("CARAPACE_TEST_NAME", "env")
+
+	test := struct {
+		Enabled bool
+		Count   int
+		Timeout time.Duration
+		Name    string `env:"CARAPACE_TEST_NAME"`
+		Default string
+	}{
+		Default: "default",
+	}
+	Register("test", &test)
+	if test.Count != 0 || test.Name != "env" {
+		t.Errorf("only the environment should be applied on registration: %#v", test)
+	}
+	if err := config.LoadSettings(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !test.Enabled || test.Count != 3 || test.Timeout != 2*time.Second || test.Name != "env" || test.Default != "default" {
+		t.Errorf("unexpected settings: %#v", test)
+	}
+
+	if err := Set("test.Count", "none"); err == nil {
+		t.Error("invalid value should fail")
+	}
+	if err := Set("test.Unknown", "1"); err == nil {
+		t.Error("unknown setting should fail")
+	}
+
+	if err := Set("test.Timeout", "5m"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := Get("test.Timeout"); err != nil || value != "5m0s" {
+		t.Errorf("expected 5m0s, got %#v (%v)", value, err)
+	}
+
+	if err := Set("test.Enabled", ""); err != nil {
+		t.Fatal(err)
+	}
+	if test.Enabled {
+		t.Error("removed setting should restore the default")
+	}
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "carapace"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"invalid": {"Mode": "bogus", "Count": 3, "Added": "by a newer version"}}`
+	if err := os.WriteFile(filepath.Join(dir, "carapace", "settings.json"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("CARAPACE_INVALID_ENABLED", "yes")
+	t.Setenv("CARAPACE_INVALID_COUNT", "none")
+	t.Setenv("CARAPACE_INVALID_LEVEL", "1")
+
+	invalid := struct {
+		Enabled bool   `env:"CARAPACE_INVALID_ENABLED"`
+		Count   int    `env:"CARAPACE_INVALID_COUNT"`
+		Mode    string `values:"first,second"`
+		Level   string `env:"CARAPACE_INVALID_LEVEL" values:"off,debug" legacy:"debug"`
+	}{
+		Mode:  "first",
+		Level: "off",
+	}
+	Register("invalid", &invalid)
+	if err := config.LoadSettings(); err == nil {
+		t.Error("invalid values should be reported")
+	}
+
+	if !invalid.Enabled || invalid.Count != 3 || invalid.Mode != "first" || invalid.Level != "debug" {
+		t.Errorf("invalid values should be ignored: %#v", invalid)
+	}
+
+	if err := Set("invalid.Mode", "bogus"); err == nil {
+		t.Error("value not in values should fail")
+	}
+	if err := Set("invalid.Mode", "second"); err != nil {
+		t.Fatal(err)
+	}
+	if invalid.Mode != "second" {
+		t.Errorf("expected second, got %#v", invalid.Mode)
+	}
+}
+
+func TestLegacyEnv(t *testing.T) {
+	t.Cleanup(func() { _ = config.LoadSettings() })
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("CARAPACE_LOG", "")
+	t.Setenv("CARAPACE_MATCH", "1")
+
+	if err := config.LoadSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if Carapace.Match != "CASE_INSENSITIVE" {
+		t.Errorf("legacy CARAPACE_MATCH=1 should be case insensitive [was: %#v]", Carapace.Match)
+	}
+}
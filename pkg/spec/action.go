@@ -0,0 +1,15 @@
+package spec
+
+import "github.com/carapace-sh/carapace"
+
+func actions(values [][]string) []carapace.Action {
+	a := make([]carapace.Action, 0, len(values))
+	for _, v := range values {
+		a = append(a, action(v))
+	}
+	return a
+}
+
+func action(values []string) carapace.Action {
+	return carapace.ActionSpec(values...)
+}
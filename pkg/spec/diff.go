@@ -0,0 +1,26 @@
+package spec
+
+import "github.com/carapace-sh/carapace/internal/spec"
+
+// Change is a difference between two specs.
+type Change = spec.Change
+
+// Compare compares given specs and classifies the changes as breaking or non-breaking.
+//
+//	changes, err := spec.Compare(released, current)
+//	if err == nil && spec.HasBreaking(changes) {
+//		// gate release
+//	}
+func Compare(oldSpec, newSpec []byte) ([]Change, error) {
+	return spec.CompareYaml(oldSpec, newSpec)
+}
+
+// HasBreaking checks whether any of given changes is breaking.
+func HasBreaking(changes []Change) bool {
+	return spec.HasBreaking(changes)
+}
+
+// Diff returns a unified diff of given specs.
+func Diff(oldSpec, newSpec []byte) string {
+	return spec.Unified("old", "new", string(oldSpec), string(newSpec))
+}
@@ -0,0 +1,91 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	oldSpec := `
+name: example
+aliases: [ex]
+args:
+  min: 0
+  max: 2
+flags:
+  -b, --bool: bool flag
+  --optarg?: optarg flag
+  --removed: removed flag
+  --values=: values flag
+  --macro=: macro flag
+persistentflags:
+  --moved: moved flag
+completion:
+  flag:
+    values: ["one\tfirst", "two", "$files"]
+    macro: ["a", "b"]
+commands:
+  - name: removed
+  - name: kept
+    flags:
+      --hidden: hidden flag
+`
+
+	newSpec := `
+name: example
+args:
+  min: 1
+  max: 2
+flags:
+  --bool=: bool flag
+  --optarg=: optarg flag
+  --values=: values flag
+  --added: added flag
+  --macro=: macro flag
+  --moved: moved flag
+exclusiveflags:
+  - [bool, added]
+completion:
+  flag:
+    values: ["one", "three"]
+    macro: ["$files"]
+commands:
+  - name: kept
+    flags:
+      --hidden&: hidden flag
+  - name: added
+`
+
+	changes, err := Compare([]byte(oldSpec), []byte(newSpec))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := []Change{
+		{Breaking: true, Path: "example", Message: "removed alias ex"},
+		{Breaking: true, Path: "example", Message: "changed positional arguments from 0..2 to 1..2"},
+		{Breaking: true, Path: "example --bool", Message: "removed shorthand -b"},
+		{Breaking: true, Path: "example --bool", Message: "now requires a value"},
+		{Breaking: true, Path: "example --moved", Message: "no longer persistent"},
+		{Breaking: true, Path: "example --optarg", Message: "now requires a value"},
+		{Breaking: true, Path: "example --removed", Message: "removed flag"},
+		{Breaking: true, Path: "example --values", Message: "removed value two"},
+		{Breaking: false, Path: "example --values", Message: "added value three"},
+		{Breaking: false, Path: "example --added", Message: "added flag"},
+		{Breaking: true, Path: "example", Message: "added exclusive flag group [added bool]"},
+		{Breaking: true, Path: "example removed", Message: "removed command"},
+		{Breaking: false, Path: "example kept --hidden", Message: "flag is now hidden"},
+		{Breaking: false, Path: "example added", Message: "added command"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, changes)
+	}
+
+	if !HasBreaking(changes) {
+		t.Error("should have breaking changes")
+	}
+
+	if changes, _ := Compare([]byte(oldSpec), []byte(oldSpec)); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
@@ -0,0 +1,124 @@
+// Package spec loads command trees from spec files
+package spec
+
+import (
+	"fmt"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/carapace-sh/carapace/internal/spec"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Load creates a command tree with completions from given spec.
+//
+//	name: example
+//	flags:
+//	  -f, --file=: file to read
+//	completion:
+//	  flag:
+//	    file: ["$files([.txt])"]
+//	  positional:
+//	    - ["one\tfirst", "two\tsecond"]
+func Load(content []byte) (*cobra.Command, error) {
+	var c spec.Command
+	if err := yaml.Unmarshal(content, &c); err != nil {
+		return nil, err
+	}
+	return command(c, nil)
+}
+
+// command creates the command for given spec.
+// It is added to parent first so that inherited persistent flags are known to flag groups and completion.
+func command(c spec.Command, parent *cobra.Command) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:     c.Name,
+		Short:   c.Description,
+		Aliases: c.Aliases,
+		GroupID: c.Group,
+		Hidden:  c.Hidden,
+	}
+
+	for definition, usage := range c.PersistentFlags {
+		if err := spec.AddFlag(cmd.PersistentFlags(), definition, usage); err != nil {
+			return nil, fmt.Errorf("%v: %w", c.Name, err)
+		}
+	}
+
+	for definition, usage := range c.Flags {
+		if err := spec.AddFlag(cmd.Flags(), definition, usage); err != nil {
+			return nil, fmt.Errorf("%v: %w", c.Name, err)
+		}
+	}
+
+	if parent != nil {
+		if cmd.GroupID != "" && !parent.ContainsGroup(cmd.GroupID) {
+			parent.AddGroup(&cobra.Group{ID: cmd.GroupID, Title: cmd.GroupID})
+		}
+		parent.AddCommand(cmd)
+	}
+
+	if c.Args != nil {
+		switch {
+		case c.Args.Max < 0:
+			cmd.Args = cobra.MinimumNArgs(c.Args.Min)
+		default:
+			cmd.Args = cobra.RangeArgs(c.Args.Min, c.Args.Max)
+		}
+		carapace.Gen(cmd).Args(c.Args.Min, c.Args.Max)
+	}
+
+	for _, g := range []struct {
+		groups [][]string
+		mark   func(flagNames ...string)
+	}{
+		{c.ExclusiveFlags, cmd.MarkFlagsMutuallyExclusive},
+		{c.RequiredTogetherFlags, cmd.MarkFlagsRequiredTogether},
+		{c.OneRequiredFlags, cmd.MarkFlagsOneRequired},
+	} {
+		for _, group := range g.groups {
+			for _, name := range group {
+				if cmd.Flag(name) == nil {
+					return nil, fmt.Errorf("%v: unknown flag in flag group: %v", c.Name, name)
+				}
+			}
+			g.mark(group...)
+		}
+	}
+
+	if err := completion(cmd, c); err != nil {
+		return nil, fmt.Errorf("%v: %w", c.Name, err)
+	}
+
+	for _, s := range c.Commands {
+		if _, err := command(s, cmd); err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
+
+func completion(cmd *cobra.Command, c spec.Command) error {
+	flagCompletion := make(carapace.ActionMap)
+	for name, values := range c.Completion.Flag {
+		if cmd.Flag(name) == nil {
+			return fmt.Errorf("unknown flag in completion: %v", name)
+		}
+		flagCompletion[name] = action(values)
+	}
+	carapace.Gen(cmd).FlagCompletion(flagCompletion)
+
+	if len(c.Completion.Positional) > 0 {
+		carapace.Gen(cmd).PositionalCompletion(actions(c.Completion.Positional)...)
+	}
+	if len(c.Completion.PositionalAny) > 0 {
+		carapace.Gen(cmd).PositionalAnyCompletion(action(c.Completion.PositionalAny))
+	}
+	if len(c.Completion.Dash) > 0 {
+		carapace.Gen(cmd).DashCompletion(actions(c.Completion.Dash)...)
+	}
+	if len(c.Completion.DashAny) > 0 {
+		carapace.Gen(cmd).DashAnyCompletion(action(c.Completion.DashAny))
+	}
+	return nil
+}
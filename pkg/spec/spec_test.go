@@ -0,0 +1,189 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/carapace-sh/carapace"
+	"github.com/carapace-sh/carapace/pkg/sandbox"
+	"github.com/carapace-sh/carapace/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+const example = `
+name: example
+description: example command
+args:
+  min: 0
+  max: 2
+persistentflags:
+  -v, --verbose*: verbose output
+flags:
+  -f, --file=: file to read
+  --format?: output format
+  -x&: hidden flag
+  --json: json output
+  --yaml: yaml output
+exclusiveflags:
+  - [json, yaml]
+completion:
+  flag:
+    file: ["$files([.txt])"]
+    format: ["json\tJSON", "yaml"]
+  positional:
+    - ["one\tfirst", "two\tsecond"]
+    - ["$directories"]
+  positionalany: ["any"]
+commands:
+  - name: sub
+    group: main
+    aliases: [s]
+    flags:
+      -r, --required=!: required flag
+`
+
+func TestLoad(t *testing.T) {
+	sandbox.Command(t, func() *cobra.Command {
+		cmd, err := Load([]byte(example))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return cmd
+	})(func(s *sandbox.Sandbox) {
+		s.Files(
+			"dirA/file.txt", "",
+			"file.txt", "",
+			"file.md", "",
+		)
+
+		s.Run("t").
+			Expect(carapace.ActionValuesDescribed(
+				"two", "second",
+			))
+
+		s.Run("su").
+			Expect(carapace.ActionValues("sub").
+				Style(style.Blue).
+				Tag("main commands"))
+
+		s.Run("one", "").
+			Expect(carapace.ActionDirectories())
+
+		s.Run("one", "dirA/", "").
+			Expect(carapace.ActionValues("any"))
+
+		s.Run("--file", "").
+			Expect(carapace.ActionValues("dirA/", "file.txt").
+				StyleF(style.ForPath).
+				NoSpace('/').
+				Tag("files").
+				Usage("file to read"))
+
+		s.Run("--format=").
+			Expect(carapace.ActionValuesDescribed(
+				"json", "JSON",
+				"yaml", "",
+			).Prefix("--format=").
+				Usage("output format"))
+
+		s.Run("--json", "--y").
+			ExpectNot(carapace.ActionValues("--yaml"))
+	})
+}
+
+func TestLoadPersistentFlags(t *testing.T) {
+	sandbox.Command(t, func() *cobra.Command {
+		cmd, err := Load([]byte(`
+name: persistent
+persistentflags:
+  --pflag=: persistent flag
+flags:
+  --local: local flag
+exclusiveflags:
+  - [pflag, local]
+completion:
+  flag:
+    pflag: [a, b]
+commands:
+  - name: sub
+  - name: inherit
+    flags:
+      --local: local flag
+    exclusiveflags:
+      - [pflag, local]
+    completion:
+      flag:
+        pflag: [x, y]
+`))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return cmd
+	})(func(s *sandbox.Sandbox) {
+		s.Run("--pflag", "").
+			Expect(carapace.ActionValues("a", "b").
+				Usage("persistent flag"))
+
+		s.Run("sub", "--pflag", "").
+			Expect(carapace.ActionValues("a", "b").
+				Usage("persistent flag"))
+
+		s.Run("inherit", "--pflag", "").
+			Expect(carapace.ActionValues("x", "y").
+				Usage("persistent flag"))
+
+		s.Run("inherit", "--local", "--p").
+			ExpectNot(carapace.ActionValues("--pflag"))
+
+		s.Run("--local", "--p").
+			ExpectNot(carapace.ActionValues("--pflag"))
+	})
+}
+
+func TestLoadFlags(t *testing.T) {
+	cmd, err := Load([]byte(example))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := cmd.Args(cmd, make([]string, 3)); err == nil {
+		t.Error("should fail with 3 arguments")
+	}
+
+	if f := cmd.Flag("file"); f == nil || f.Shorthand != "f" || f.Value.Type() != "string" {
+		t.Errorf("unexpected flag: %#v", f)
+	}
+
+	if f := cmd.Flag("format"); f == nil || f.NoOptDefVal == "" {
+		t.Errorf("unexpected flag: %#v", f)
+	}
+
+	if f := cmd.Flag("x"); f == nil || !f.Hidden || f.Value.Type() != "bool" {
+		t.Errorf("unexpected flag: %#v", f)
+	}
+
+	if f := cmd.PersistentFlags().Lookup("verbose"); f == nil || f.Value.Type() != "count" {
+		t.Errorf("unexpected flag: %#v", f)
+	}
+
+	subCmd, _, err := cmd.Find([]string{"s"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if f := subCmd.Flag("required"); f == nil || f.Annotations[cobra.BashCompOneRequiredFlag][0] != "true" {
+		t.Errorf("unexpected flag: %#v", f)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for _, s := range []string{
+		"name: example\nflags:\n  -fail, --file=: invalid shorthand",
+		"name: example\nflags:\n  file: missing dash",
+		"name: example\nexclusiveflags:\n  - [unknown]",
+		"name: example\nrequiredtogetherflags:\n  - [unknown]",
+		"name: example\ncompletion:\n  flag:\n    unknown: [value]",
+	} {
+		if _, err := Load([]byte(s)); err == nil {
+			t.Errorf("should fail: %v", s)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package style
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/carapace-sh/carapace/internal/config"
 )
 
@@ -19,15 +23,74 @@ import (
 //	}
 func Register(name string, i interface{}) { config.RegisterStyle(name, i) }
 
-// Set a style
+// Set a style (an empty value removes it from the config file)
 //
 //	Set("carapace.Value", "bold magenta")
-func Set(key, value string) error { return config.SetStyle(key, value) }
+func Set(key, value string) error {
+	if _, err := config.GetStyle(key); err != nil {
+		return err
+	}
+	if err := Validate(value); err != nil {
+		return err
+	}
+	return config.SetStyle(key, value)
+}
+
+// Get the effective value of a style
+//
+//	Get("carapace.Value")
+func Get(key string) (string, error) { return config.GetStyle(key) }
+
+// Reset a style, style configuration or all styles (empty key) to default
+//
+//	Reset("carapace.Value")
+//	Reset("carapace")
+func Reset(key string) error {
+	if key != "" && !strings.Contains(key, ".") {
+		if _, err := config.GetStyleFields(key); err != nil {
+			return err
+		}
+	} else if key != "" {
+		if _, err := config.GetStyle(key); err != nil {
+			return err
+		}
+	}
+	return config.ResetStyle(key)
+}
+
+// Export the effective styles as theme
+//
+//	{"carapace": {"Value": "bold magenta"}}
+func Export() ([]byte, error) { return config.ExportStyles() }
+
+// Import a theme (merged into the configured styles)
+func Import(content []byte) error {
+	var styles map[string]map[string]string
+	if err := json.Unmarshal(content, &styles); err != nil {
+		return fmt.Errorf("invalid theme: %v", err.Error())
+	}
+
+	for name, fields := range styles {
+		for field, value := range fields {
+			key := name + "." + field
+			if _, err := config.GetStyle(key); err != nil {
+				return err
+			}
+			if err := Validate(value); err != nil {
+				return fmt.Errorf("%v: %v", key, err.Error())
+			}
+			fields[field] = strings.Replace(value, ",", " ", -1)
+		}
+	}
+	return config.ImportStyles(styles)
+}
 
 type carapace struct {
 	Value       string `description:"default style for values" tag:"core styles"`
 	Description string `description:"default style for descriptions" tag:"core styles"`
 	Error       string `description:"default style for errors" tag:"core styles"`
+	Warning     string `description:"default style for warnings" tag:"core styles"`
+	Info        string `description:"default style for info messages" tag:"core styles"`
 	Usage       string `description:"default style for usage" tag:"core styles"`
 
 	KeywordAmbiguous string `description:"keyword describing a ambiguous state" tag:"keyword styles"`
@@ -60,12 +123,18 @@ type carapace struct {
 	FlagMultiArg string `description:"flag with multiple arguments" tag:"flag styles"`
 	FlagNoArg    string `description:"flag without argument" tag:"flag styles"`
 	FlagOptArg   string `description:"flag with optional argument" tag:"flag styles"`
+
+	Directory  string `description:"directory (if LS_COLORS is not set)" tag:"path styles"`
+	Executable string `description:"executable (if LS_COLORS is not set)" tag:"path styles"`
+	Symlink    string `description:"symbolic link (if LS_COLORS is not set)" tag:"path styles"`
 }
 
 var Carapace = carapace{
 	Value:       Default,
 	Description: Dim,
 	Error:       Of(Bold, Red),
+	Warning:     Of(Bold, Yellow),
+	Info:        Of(Bold, Blue),
 	Usage:       Dim,
 
 	KeywordAmbiguous: Yellow,
@@ -98,6 +167,10 @@ var Carapace = carapace{
 	FlagMultiArg: Magenta,
 	FlagNoArg:    Default,
 	FlagOptArg:   Yellow,
+
+	Directory:  Of(Blue, Bold),
+	Executable: Of(Green, Bold),
+	Symlink:    Of(Cyan, Bold),
 }
 
 // Highlight returns the style for given level (0..n)
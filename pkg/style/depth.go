@@ -0,0 +1,214 @@
+package style
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/config"
+	"github.com/carapace-sh/carapace/pkg/settings"
+)
+
+// Depth is the amount of colors supported by the terminal.
+type Depth int
+
+const (
+	Depth16        Depth = 16
+	Depth256       Depth = 256
+	DepthTrueColor Depth = 1 << 24
+)
+
+// DetectDepth determines the color depth from the `carapace.ColorDepth` setting,
+// `COLORTERM` and `TERM`. It defaults to true color if `TERM` is not set.
+func DetectDepth() Depth {
+	switch settings.Carapace.ColorDepth {
+	case "16":
+		return Depth16
+	case "256":
+		return Depth256
+	case "truecolor":
+		return DepthTrueColor
+	}
+
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return DepthTrueColor
+	}
+	if os.Getenv("WT_SESSION") != "" {
+		return DepthTrueColor // windows terminal
+	}
+
+	switch term := os.Getenv("TERM"); {
+	case term == "":
+		return DepthTrueColor
+	case strings.Contains(term, "direct"):
+		return DepthTrueColor
+	case strings.Contains(term, "256color"):
+		return Depth256
+	default:
+		return Depth16
+	}
+}
+
+// Downgrade maps true colors and 256 colors to the nearest color supported by given depth.
+//
+//	Downgrade("bold #d35673", Depth256) // bold color168
+//	Downgrade("bold #d35673", Depth16)  // bold red
+func Downgrade(s string, d Depth) string {
+	if d >= DepthTrueColor || s == "" {
+		return s
+	}
+
+	words := strings.Fields(strings.Replace(s, ",", " ", -1))
+	for index, word := range words {
+		prefix := ""
+		for _, p := range []string{"fg-", "bg-"} {
+			if strings.HasPrefix(word, p) {
+				prefix, word = p, word[len(p):]
+			}
+		}
+
+		if r, g, b, ok := parseHex(word); ok {
+			switch d {
+			case Depth256:
+				words[index] = prefix + "color" + strconv.Itoa(nearest256(r, g, b))
+			default:
+				words[index] = prefix + nearest16(r, g, b)
+			}
+		} else if i, ok := parseXTerm256(word); ok && d < Depth256 {
+			if i < 16 {
+				words[index] = prefix + ansiNames[i]
+			} else {
+				r, g, b := xterm256RGB(i)
+				words[index] = prefix + nearest16(r, g, b)
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// SetDepth downgrades all registered styles to given depth.
+func SetDepth(d Depth) {
+	config.MapStyles(func(s string) string { return Downgrade(s, d) })
+}
+
+var ansiNames = []string{
+	Black, Red, Green, Yellow, Blue, Magenta, Cyan, White,
+	BrightBlack, BrightRed, BrightGreen, BrightYellow, BrightBlue, BrightMagenta, BrightCyan, BrightWhite,
+}
+
+func parseHex(s string) (r, g, b int, ok bool) {
+	if !strings.HasPrefix(s, "#") || len(s) != 7 {
+		return
+	}
+	rgb, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return
+	}
+	return int(rgb >> 16 & 0xff), int(rgb >> 8 & 0xff), int(rgb & 0xff), true
+}
+
+func parseXTerm256(s string) (int, bool) {
+	if !strings.HasPrefix(s, "color") {
+		return 0, false
+	}
+	i, err := strconv.Atoi(s[len("color"):])
+	return i, err == nil && i >= 0 && i < 256
+}
+
+var cubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+// xterm256RGB returns the rgb value of given xterm 256-color palette index.
+func xterm256RGB(i int) (r, g, b int) {
+	switch {
+	case i < 16:
+		standard := [16][3]int{
+			{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0}, {0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+			{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+		}
+		return standard[i][0], standard[i][1], standard[i][2]
+	case i < 232:
+		i -= 16
+		return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]
+	default:
+		gray := 8 + (i-232)*10
+		return gray, gray, gray
+	}
+}
+
+// nearest256 returns the nearest color of the 6x6x6 cube or grayscale ramp.
+func nearest256(r, g, b int) int {
+	cubeIndex := func(v int) int {
+		for index := len(cubeLevels) - 1; index > 0; index-- {
+			if v >= (cubeLevels[index]+cubeLevels[index-1])/2 {
+				return index
+			}
+		}
+		return 0
+	}
+	cube := 16 + 36*cubeIndex(r) + 6*cubeIndex(g) + cubeIndex(b)
+
+	grayIndex := ((r+g+b)/3 - 3) / 10
+	if grayIndex < 0 {
+		grayIndex = 0
+	} else if grayIndex > 23 {
+		grayIndex = 23
+	}
+	gray := 232 + grayIndex
+
+	if distanceRGB(r, g, b, cube) <= distanceRGB(r, g, b, gray) {
+		return cube
+	}
+	return gray
+}
+
+// nearest16 returns the ansi color with the nearest hue (or gray level for unsaturated colors).
+func nearest16(r, g, b int) string {
+	max, min := r, r
+	for _, v := range []int{g, b} {
+		if v > max {
+			max = v
+		}
+		if v < min {
+			min = v
+		}
+	}
+
+	if max-min < 48 {
+		switch lightness := (r + g + b) / 3; {
+		case lightness < 64:
+			return Black
+		case lightness < 160:
+			return BrightBlack
+		case lightness < 224:
+			return White
+		default:
+			return BrightWhite
+		}
+	}
+
+	var hue float64
+	switch delta := float64(max - min); max {
+	case r:
+		hue = 60 * float64(g-b) / delta
+	case g:
+		hue = 60 * (2 + float64(b-r)/delta)
+	default:
+		hue = 60 * (4 + float64(r-g)/delta)
+	}
+	if hue < 0 {
+		hue += 360
+	}
+
+	colors := []string{Red, Yellow, Green, Cyan, Blue, Magenta}
+	color := colors[int(hue/60+0.5)%6]
+	if max > 200 {
+		return "bright-" + color
+	}
+	return color
+}
+
+func distanceRGB(r, g, b, i int) int {
+	pr, pg, pb := xterm256RGB(i)
+	return (r-pr)*(r-pr) + (g-pg)*(g-pg) + (b-pb)*(b-pb)
+}
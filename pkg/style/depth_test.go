@@ -0,0 +1,29 @@
+package style
+
+import "testing"
+
+func TestDowngrade(t *testing.T) {
+	for _, test := range []struct {
+		style    string
+		depth    Depth
+		expected string
+	}{
+		{"bold #d35673", DepthTrueColor, "bold #d35673"},
+		{"bold #d35673", Depth256, "bold color168"},
+		{"bold #d35673", Depth16, "bold bright-red"},
+		{"#89b4fa", Depth16, "bright-blue"},
+		{"#6c7086", Depth16, "bright-black"},
+		{"#008000", Depth16, "green"},
+		{"bg-#ffffff", Depth256, "bg-color231"},
+		{"#808080", Depth256, "color244"},
+		{"color1", Depth16, "red"},
+		{"fg-color12", Depth16, "fg-bright-blue"},
+		{"color196", Depth16, "bright-red"},
+		{"color196", Depth256, "color196"},
+		{"dim,blue", Depth16, "dim blue"},
+	} {
+		if actual := Downgrade(test.style, test.depth); actual != test.expected {
+			t.Errorf("Downgrade(%#v, %v): expected %#v, got %#v", test.style, test.depth, test.expected, actual)
+		}
+	}
+}
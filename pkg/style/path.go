@@ -1,6 +1,8 @@
 package style
 
 import (
+	"strings"
+
 	"github.com/carapace-sh/carapace/third_party/github.com/elves/elvish/pkg/cli/lscolors"
 	"github.com/carapace-sh/carapace/third_party/github.com/elves/elvish/pkg/ui"
 )
@@ -18,14 +20,15 @@ func ForPath(path string, sc Context) string {
 	if abs, err := sc.Abs(path); err == nil {
 		path = abs
 	}
-	return fromSGR(lscolors.GetColorist(sc.Getenv("LS_COLORS")).GetStyle(path))
+	lsColors := LsColors(sc)
+	return withPathStyles(lsColors, fromSGR(lscolors.GetColorist(lsColors).GetStyle(path)))
 }
 
 // ForPath returns the style for given path by extension only
 //
 //	/tmp/non/existing/file.txt
 func ForPathExt(path string, sc Context) string {
-	return fromSGR(lscolors.GetColorist(sc.Getenv("LS_COLORS")).GetStyleExt(path))
+	return fromSGR(lscolors.GetColorist(LsColors(sc)).GetStyleExt(path))
 }
 
 // ForExtension returns the style for given extension
@@ -35,6 +38,50 @@ func ForExtension(path string, sc Context) string {
 	return ForPathExt("."+path, sc)
 }
 
+// withPathStyles replaces the default styles of `di`, `ex` and `ln` with the path styles if LS_COLORS is not set.
+func withPathStyles(lsColors, s string) string {
+	if lsColors != "" {
+		return s
+	}
+	switch s {
+	case fromSGR("01;34"):
+		return Carapace.Directory
+	case fromSGR("01;32"):
+		return Carapace.Executable
+	case fromSGR("01;36"):
+		return Carapace.Symlink
+	default:
+		return s
+	}
+}
+
+// LsColors returns LS_COLORS extended by EZA_COLORS.
+// LS_COLORS is ignored if EZA_COLORS contains `reset`.
+func LsColors(sc Context) string {
+	lsColors, ezaColors := sc.Getenv("LS_COLORS"), sc.Getenv("EZA_COLORS")
+
+	entries := make([]string, 0)
+	for _, entry := range strings.Split(ezaColors, ":") {
+		switch entry {
+		case "":
+		case "reset":
+			lsColors = ""
+		default:
+			entries = append(entries, entry)
+		}
+	}
+	ezaColors = strings.Join(entries, ":")
+
+	switch {
+	case lsColors == "":
+		return ezaColors
+	case ezaColors == "":
+		return lsColors
+	default:
+		return lsColors + ":" + ezaColors
+	}
+}
+
 func fromSGR(sgr string) string {
 	s := ui.StyleFromSGR(sgr)
 	result := []string{}
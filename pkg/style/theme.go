@@ -0,0 +1,190 @@
+package style
+
+import (
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/config"
+)
+
+// Theme is a set of styles by style configuration and field.
+//
+//	Theme{"carapace": {"Value": Blue}}
+type Theme map[string]map[string]string
+
+// RegisterTheme registers a theme selectable with the `carapace.Theme` setting.
+// It is applied on top of the defaults and overridden by the configured styles.
+func RegisterTheme(name string, t Theme) { config.RegisterTheme(name, t) }
+
+// Themes returns the names of registered themes.
+func Themes() []string { return config.GetThemes() }
+
+var themes = map[string]Theme{
+	"dark": {
+		"carapace": {
+			"Value":       Default,
+			"Description": Dim,
+			"Error":       Of(Bold, BrightRed),
+			"Warning":     Of(Bold, BrightYellow),
+			"Info":        Of(Bold, BrightBlue),
+			"Usage":       Dim,
+
+			"KeywordAmbiguous": BrightYellow,
+			"KeywordNegative":  BrightRed,
+			"KeywordPositive":  BrightGreen,
+			"KeywordUnknown":   Of(Dim, White),
+
+			"Highlight1": BrightBlue,
+			"Highlight2": BrightYellow,
+			"Highlight3": BrightMagenta,
+			"Highlight4": BrightCyan,
+			"Highlight5": BrightGreen,
+
+			"FlagArg":      BrightBlue,
+			"FlagMultiArg": BrightMagenta,
+			"FlagNoArg":    Default,
+			"FlagOptArg":   BrightYellow,
+		},
+	},
+	"light": {
+		"carapace": {
+			"Value":       Default,
+			"Description": XTerm256Color(242),
+			"Error":       Of(Bold, Red),
+			"Warning":     Of(Bold, XTerm256Color(130)),
+			"Info":        Of(Bold, Blue),
+			"Usage":       XTerm256Color(242),
+
+			"KeywordAmbiguous": XTerm256Color(130),
+			"KeywordNegative":  Red,
+			"KeywordPositive":  XTerm256Color(28),
+			"KeywordUnknown":   XTerm256Color(242),
+
+			"LogLevelDebug":   XTerm256Color(242),
+			"LogLevelInfo":    XTerm256Color(28),
+			"LogLevelWarning": XTerm256Color(130),
+
+			"Highlight1": Blue,
+			"Highlight2": XTerm256Color(130),
+			"Highlight3": Magenta,
+			"Highlight4": XTerm256Color(30),
+			"Highlight5": XTerm256Color(28),
+
+			"FlagArg":      Blue,
+			"FlagMultiArg": Magenta,
+			"FlagNoArg":    Default,
+			"FlagOptArg":   XTerm256Color(130),
+		},
+	},
+	"high-contrast": {
+		"carapace": {
+			"Value":       Bold,
+			"Description": Default,
+			"Error":       Of(Bold, Underlined, BrightRed),
+			"Warning":     Of(Bold, Underlined, BrightYellow),
+			"Info":        Of(Bold, Underlined, BrightCyan),
+			"Usage":       Italic,
+
+			"KeywordAmbiguous": Of(Bold, BrightYellow),
+			"KeywordNegative":  Of(Bold, BrightRed),
+			"KeywordPositive":  Of(Bold, BrightGreen),
+			"KeywordUnknown":   Bold,
+
+			"Highlight1":  Of(Bold, BrightBlue),
+			"Highlight2":  Of(Bold, BrightYellow),
+			"Highlight3":  Of(Bold, BrightMagenta),
+			"Highlight4":  Of(Bold, BrightCyan),
+			"Highlight5":  Of(Bold, BrightGreen),
+			"Highlight6":  BrightBlue,
+			"Highlight7":  BrightYellow,
+			"Highlight8":  BrightMagenta,
+			"Highlight9":  BrightCyan,
+			"Highlight10": BrightGreen,
+			"Highlight11": Of(Bold, Underlined),
+			"Highlight12": Underlined,
+
+			"FlagArg":      Of(Bold, BrightBlue),
+			"FlagMultiArg": Of(Bold, BrightMagenta),
+			"FlagNoArg":    Bold,
+			"FlagOptArg":   Of(Bold, BrightYellow),
+		},
+	},
+	"catppuccin": { // mocha
+		"carapace": {
+			"Value":       "#cdd6f4",
+			"Description": "#6c7086",
+			"Error":       Of(Bold, "#f38ba8"),
+			"Warning":     Of(Bold, "#f9e2af"),
+			"Info":        Of(Bold, "#89b4fa"),
+			"Usage":       "#6c7086",
+
+			"KeywordAmbiguous": "#f9e2af",
+			"KeywordNegative":  "#f38ba8",
+			"KeywordPositive":  "#a6e3a1",
+			"KeywordUnknown":   "#6c7086",
+
+			"LogLevelTrace":    "#89b4fa",
+			"LogLevelDebug":    "#6c7086",
+			"LogLevelInfo":     "#a6e3a1",
+			"LogLevelWarning":  "#f9e2af",
+			"LogLevelError":    "#cba6f7",
+			"LogLevelCritical": "#f38ba8",
+			"LogLevelFatal":    "#94e2d5",
+
+			"Highlight1":  "#89b4fa",
+			"Highlight2":  "#f9e2af",
+			"Highlight3":  "#cba6f7",
+			"Highlight4":  "#94e2d5",
+			"Highlight5":  "#a6e3a1",
+			"Highlight6":  "#74c7ec",
+			"Highlight7":  "#fab387",
+			"Highlight8":  "#f5c2e7",
+			"Highlight9":  "#89dceb",
+			"Highlight10": "#b4befe",
+			"Highlight11": Of(Bold, "#cdd6f4"),
+			"Highlight12": Of(Bold, "#a6adc8"),
+
+			"FlagArg":      "#89b4fa",
+			"FlagMultiArg": "#cba6f7",
+			"FlagNoArg":    "#cdd6f4",
+			"FlagOptArg":   "#f9e2af",
+		},
+	},
+}
+
+func init() {
+	for name, theme := range themes {
+		RegisterTheme(name, theme)
+	}
+}
+
+// lsColorsMapping maps LS_COLORS/EZA_COLORS keys to the path styles.
+var lsColorsMapping = map[string]string{
+	"di": "Directory",  // directory
+	"ex": "Executable", // executable
+	"ln": "Symlink",    // symbolic link
+}
+
+// FromLsColors creates a theme from LS_COLORS/EZA_COLORS definitions (backgrounds are ignored).
+//
+//	FromLsColors("di=01;34:ex=01;32") // Theme{"carapace": {"Directory": "blue bold", "Executable": "green bold"}}
+func FromLsColors(s string) Theme {
+	fields := make(map[string]string)
+	for _, entry := range strings.Split(s, ":") {
+		splitted := strings.SplitN(entry, "=", 2)
+		if len(splitted) != 2 {
+			continue
+		}
+
+		styles := make([]string, 0)
+		for _, word := range strings.Fields(fromSGR(splitted[1])) {
+			if !strings.HasPrefix(word, "bg-") {
+				styles = append(styles, word)
+			}
+		}
+
+		if field, ok := lsColorsMapping[splitted[0]]; ok {
+			fields[field] = Of(styles...)
+		}
+	}
+	return Theme{"carapace": fields}
+}
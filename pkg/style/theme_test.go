@@ -0,0 +1,135 @@
+package style
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carapace-sh/carapace/internal/config"
+)
+
+func TestThemes(t *testing.T) {
+	for name, theme := range themes {
+		for styleConfig, fields := range theme {
+			for field, value := range fields {
+				key := styleConfig + "." + field
+				if _, err := config.GetStyle(key); err != nil {
+					t.Errorf("%v: %v", name, err)
+				}
+				if err := Validate(value); err != nil {
+					t.Errorf("%v: %v: %v", name, key, err)
+				}
+			}
+		}
+	}
+}
+
+func TestFromLsColors(t *testing.T) {
+	expected := Theme{"carapace": {
+		"Directory":  "blue bold",
+		"Executable": "green",
+		"Symlink":    "#ff0000",
+	}}
+	if actual := FromLsColors("di=01;34:pi=40;33:ex=32:*.go=36:ga=32:ln=38;2;255;0;0"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+}
+
+type testContext map[string]string
+
+func (c testContext) Abs(s string) (string, error) { return s, nil }
+func (c testContext) Getenv(key string) string     { return c[key] }
+func (c testContext) LookupEnv(key string) (string, bool) {
+	v, ok := c[key]
+	return v, ok
+}
+
+func TestLsColors(t *testing.T) {
+	_test := func(lsColors, ezaColors, expected string) {
+		if actual := LsColors(testContext{"LS_COLORS": lsColors, "EZA_COLORS": ezaColors}); actual != expected {
+			t.Errorf("expected %#v, got %#v", expected, actual)
+		}
+	}
+
+	_test("", "", "")
+	_test("di=34", "", "di=34")
+	_test("", "ex=32", "ex=32")
+	_test("di=34", "ex=32", "di=34:ex=32")
+	_test("di=34", "reset:ex=32", "ex=32")
+	_test("di=34", "reset", "")
+}
+
+func TestLoadUnknownTheme(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "carapace"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "carapace", "styles.json"), []byte(`{"themetest": {"Value": "red"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.LoadSettings() }) // runs after the environment is restored
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("CARAPACE_THEME", "unknown")
+	if err := config.LoadSettings(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &struct{ Value string }{Value: "blue"}
+	config.RegisterStyle("themetest", s)
+
+	if err := config.Load(); err == nil || !strings.Contains(err.Error(), "unknown theme") {
+		t.Errorf("expected unknown theme error [was: %v]", err)
+	}
+	if s.Value != "red" {
+		t.Errorf("styles should be loaded despite the unknown theme [was: %v]", s.Value)
+	}
+}
+
+func TestWithPathStyles(t *testing.T) {
+	if actual := withPathStyles("", fromSGR("01;34")); actual != Carapace.Directory {
+		t.Errorf("expected %#v, got %#v", Carapace.Directory, actual)
+	}
+	if actual := withPathStyles("di=01;34", fromSGR("01;34")); actual != "blue bold" {
+		t.Errorf("expected %#v, got %#v", "blue bold", actual)
+	}
+	if actual := withPathStyles("", fromSGR("01;35")); actual != "magenta bold" {
+		t.Errorf("expected %#v, got %#v", "magenta bold", actual)
+	}
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "carapace"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"carapace": {"Value": "magenta"}, "empty": {}}`
+	file := filepath.Join(dir, "carapace", "styles.json")
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Cleanup(func() { Carapace.Value = Default })
+
+	exported, err := Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var theme Theme
+	if err := json.Unmarshal(exported, &theme); err != nil {
+		t.Fatal(err)
+	}
+	if theme["carapace"]["Value"] != "magenta" {
+		t.Errorf("should export the configured style [was: %#v]", theme["carapace"]["Value"])
+	}
+	if theme["carapace"]["Error"] != Carapace.Error {
+		t.Errorf("should export the default style [was: %#v]", theme["carapace"]["Error"])
+	}
+
+	if unchanged, err := os.ReadFile(file); err != nil || string(unchanged) != content {
+		t.Errorf("config file should not be modified [was: %#v]", string(unchanged))
+	}
+}
@@ -0,0 +1,100 @@
+package style
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/carapace-sh/carapace/third_party/github.com/elves/elvish/pkg/ui"
+)
+
+// Validate checks whether given style can be parsed.
+//
+//	Validate("bold blu") // unknown style: 'blu' (did you mean: blue?)
+func Validate(s string) error {
+	for _, word := range strings.Fields(strings.Replace(s, ",", " ", -1)) {
+		if ui.ParseStyling(word) == nil {
+			if suggestions := suggest(word); len(suggestions) > 0 {
+				return fmt.Errorf("unknown style: '%v' (did you mean: %v?)", word, strings.Join(suggestions, ", "))
+			}
+			return fmt.Errorf("unknown style: '%v'", word)
+		}
+	}
+	return nil
+}
+
+// names returns the named styles (colors and attributes).
+func names() []string {
+	colors := []string{
+		Black, Red, Green, Yellow, Blue, Magenta, Cyan, White,
+		BrightBlack, BrightRed, BrightGreen, BrightYellow, BrightBlue, BrightMagenta, BrightCyan, BrightWhite,
+	}
+
+	n := []string{"default", "bg-default", Bold, Dim, Italic, Underlined, Blink, Inverse}
+	for _, color := range colors {
+		n = append(n, color, "bg-"+color)
+	}
+	return n
+}
+
+// suggest returns the named styles closest to given word.
+func suggest(word string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	candidates := make([]candidate, 0)
+	for _, name := range names() {
+		if d := distance(word, name); d <= 2 {
+			candidates = append(candidates, candidate{name, d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	suggestions := make([]string, 0, 3)
+	for index, c := range candidates {
+		if index == 3 {
+			break
+		}
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
+
+// distance returns the levenshtein distance between given strings.
+func distance(s, t string) int {
+	previous := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		current := make([]int, len(t)+1)
+		current[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(t)]
+}
+
+func min(i ...int) int {
+	m := i[0]
+	for _, v := range i[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
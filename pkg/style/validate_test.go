@@ -0,0 +1,19 @@
+package style
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	for _, s := range []string{"", "bold blue", "bold,bg-bright-red", "#ff00ff", "color123", Of(Dim, Italic)} {
+		if err := Validate(s); err != nil {
+			t.Errorf("expected %#v to be valid: %v", s, err)
+		}
+	}
+
+	if err := Validate("bold blu"); err == nil || err.Error() != "unknown style: 'blu' (did you mean: blue, bold?)" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := Validate("unknown"); err == nil || err.Error() != "unknown style: 'unknown'" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package carapace
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/carapace-sh/carapace/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+// specCompletion returns the symbolic representation of completions registered for given command.
+func specCompletion(cmd *cobra.Command) spec.Completion {
+	entry := storage.get(cmd)
+	completion := spec.Completion{
+		Flag:          make(map[string][]string),
+		Positional:    specActions(entry.positional),
+		PositionalAny: specAction(entry.positionalAny),
+		Dash:          specActions(entry.dash),
+		DashAny:       specAction(entry.dashAny),
+	}
+
+	entry.flagMutex.RLock()
+	defer entry.flagMutex.RUnlock()
+	for name, action := range entry.flag {
+		if action.spec != nil {
+			completion.Flag[name] = action.spec
+		}
+	}
+	return completion
+}
+
+func specAction(a *Action) []string {
+	if a == nil {
+		return nil
+	}
+	return a.spec
+}
+
+// specActions returns the symbolic representation of given actions with trailing unknown ones removed.
+func specActions(actions []Action) [][]string {
+	s := make([][]string, 0, len(actions))
+	for _, action := range actions {
+		if action.spec == nil {
+			s = append(s, []string{})
+		} else {
+			s = append(s, action.spec)
+		}
+	}
+
+	for len(s) > 0 && len(s[len(s)-1]) == 0 {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// specValues returns the symbolic representation of static values (`value\tdescription`).
+// A descriptionIndex of 0 indicates values without description.
+func specValues(step, descriptionIndex int, values ...string) []string {
+	if len(values)%step != 0 {
+		return nil
+	}
+
+	s := make([]string, 0, len(values)/step)
+	for i := 0; i < len(values); i += step {
+		value := values[i]
+		if value == "" {
+			continue
+		}
+		if strings.HasPrefix(value, "$") {
+			value = `\` + value // escape macro prefix
+		}
+		if descriptionIndex > 0 && values[i+descriptionIndex] != "" {
+			value += "\t" + values[i+descriptionIndex]
+		}
+		s = append(s, value)
+	}
+	return s
+}
+
+// specMacro returns the symbolic representation of a macro (`$files([".go"])`).
+func specMacro(name string, args ...string) string {
+	if len(args) == 0 {
+		return "$" + name
+	}
+	m, _ := json.Marshal(args)
+	return "$" + name + "(" + string(m) + ")"
+}
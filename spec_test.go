@@ -0,0 +1,178 @@
+package carapace
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carapace-sh/carapace/internal/spec"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSpec(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:  "spec",
+		Args: cobra.RangeArgs(1, 2),
+		Run:  func(cmd *cobra.Command, args []string) {},
+	}
+	cmd.Flags().String("file", "", "file flag")
+	cmd.Flags().String("format", "", "format flag")
+	cmd.Flags().Bool("json", false, "json flag")
+	cmd.Flags().Bool("yaml", false, "yaml flag")
+	cmd.Flags().String("user", "", "user flag")
+	cmd.Flags().String("password", "", "password flag")
+	cmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	cmd.MarkFlagsRequiredTogether("user", "password")
+	cmd.PersistentFlags().Bool("verbose", false, "verbose flag")
+
+	subCmd := &cobra.Command{
+		Use: "sub",
+		Run: func(cmd *cobra.Command, args []string) {},
+	}
+	subCmd.Flags().Bool("quiet", false, "quiet flag")
+	cmd.AddCommand(subCmd)
+	subCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+
+	Gen(cmd).Args(1, 2)
+	Gen(cmd).FlagCompletion(ActionMap{
+		"file":   ActionFiles(".go", ".mod"),
+		"format": ActionValuesDescribed("json", "JSON", "yaml", "").Tag("formats"),
+		"user": ActionCallback(func(c Context) Action {
+			return ActionValues()
+		}),
+	})
+	Gen(cmd).PositionalCompletion(
+		ActionValues("one", "$two"),
+		Batch(
+			ActionDirectories(),
+			ActionExecutables(),
+		).ToA(),
+	)
+
+	var c spec.Command
+	if err := yaml.Unmarshal([]byte(spec.Spec(cmd, specCompletion)), &c); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if expected := (&spec.Args{Min: 1, Max: 2}); !reflect.DeepEqual(c.Args, expected) {
+		t.Errorf("expected %#v, got %#v", expected, c.Args)
+	}
+
+	if expected := [][]string{{"json", "yaml"}}; !reflect.DeepEqual(c.ExclusiveFlags, expected) {
+		t.Errorf("expected %#v, got %#v", expected, c.ExclusiveFlags)
+	}
+
+	if expected := [][]string{{"user", "password"}}; !reflect.DeepEqual(c.RequiredTogetherFlags, expected) {
+		t.Errorf("expected %#v, got %#v", expected, c.RequiredTogetherFlags)
+	}
+
+	if expected := [][]string{{"verbose", "quiet"}}; !reflect.DeepEqual(c.Commands[0].ExclusiveFlags, expected) {
+		t.Errorf("expected %#v, got %#v", expected, c.Commands[0].ExclusiveFlags)
+	}
+
+	expected := spec.Completion{
+		Flag: map[string][]string{
+			"file":   {`$files([".go",".mod"])`},
+			"format": {"json\tJSON", "yaml"},
+		},
+		Positional: [][]string{
+			{"one", `\$two`},
+			{"$directories", "$executables"},
+		},
+	}
+	if !reflect.DeepEqual(c.Completion, expected) {
+		t.Errorf("expected %#v, got %#v", expected, c.Completion)
+	}
+}
+
+func TestSpecArgs(t *testing.T) {
+	invoked := false
+	for _, test := range []struct {
+		args     cobra.PositionalArgs
+		arity    []int
+		expected *spec.Args
+	}{
+		{nil, nil, nil},
+		{cobra.NoArgs, nil, &spec.Args{Min: 0, Max: 0}},
+		{cobra.ArbitraryArgs, nil, &spec.Args{Min: 0, Max: -1}},
+		{cobra.ExactArgs(2), nil, nil},
+		{cobra.ExactArgs(2), []int{2, 2}, &spec.Args{Min: 2, Max: 2}},
+		{cobra.MinimumNArgs(1), []int{1, -1}, &spec.Args{Min: 1, Max: -1}},
+		{func(cmd *cobra.Command, args []string) error { invoked = true; return nil }, nil, nil},
+	} {
+		cmd := &cobra.Command{Use: "args", Args: test.args}
+		if test.arity != nil {
+			Gen(cmd).Args(test.arity[0], test.arity[1])
+		}
+
+		var c spec.Command
+		if err := yaml.Unmarshal([]byte(spec.Spec(cmd, specCompletion)), &c); err != nil {
+			t.Fatal(err.Error())
+		}
+		if !reflect.DeepEqual(c.Args, test.expected) {
+			t.Errorf("expected %#v, got %#v", test.expected, c.Args)
+		}
+	}
+
+	if invoked {
+		t.Error("validator should not be invoked")
+	}
+}
+
+func TestSpecFormat(t *testing.T) {
+	cmd := &cobra.Command{Use: "format", Short: "format command"}
+	cmd.Flags().StringP("file", "f", "", "file | flag")
+	Gen(cmd).FlagCompletion(ActionMap{
+		"file": ActionValuesDescribed("one", "first", "two", "second"),
+	})
+	c := spec.New(cmd, specCompletion)
+
+	s, err := spec.Format(c, "json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var fromJSON spec.Command
+	if err := json.Unmarshal([]byte(s), &fromJSON); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(fromJSON.Completion.Flag, c.Completion.Flag) || !reflect.DeepEqual(fromJSON.Flags, c.Flags) {
+		t.Errorf("json mismatch: %v", s)
+	}
+
+	s, _ = spec.Format(c, "markdown")
+	for _, expected := range []string{
+		"# format\n",
+		"format command\n",
+		"| file | `-f, --file=` | file \\| flag | `one`, `two` |",
+	} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("markdown should contain %#v: %v", expected, s)
+		}
+	}
+
+	if _, err := spec.Format(c, "unknown"); err == nil {
+		t.Error("should fail on unknown format")
+	}
+}
+
+func TestSpecSchema(t *testing.T) {
+	var schema struct {
+		Properties map[string]interface{} `json:"properties"`
+		Required   []string               `json:"required"`
+	}
+	if err := json.Unmarshal([]byte(spec.Schema()), &schema); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, property := range []string{"name", "aliases", "args", "flags", "completion", "commands"} {
+		if _, ok := schema.Properties[property]; !ok {
+			t.Errorf("missing property: %v", property)
+		}
+	}
+
+	if !reflect.DeepEqual(schema.Required, []string{"name"}) {
+		t.Errorf("unexpected required properties: %v", schema.Required)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/carapace-sh/carapace/internal/common"
+	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/carapace-sh/carapace/internal/uid"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -85,10 +86,17 @@ func (s _storage) hasFlag(cmd *cobra.Command, name string) bool {
 }
 
 func (s _storage) getFlag(cmd *cobra.Command, name string) Action {
-	if flag := cmd.LocalFlags().Lookup(name); flag == nil && cmd.HasParent() {
+	entry := s.get(cmd)
+	entry.flagMutex.RLock()
+	_, overridden := entry.flag[name] // completion for an inherited persistent flag
+	entry.flagMutex.RUnlock()
+
+	if flag := cmd.LocalFlags().Lookup(name); flag == nil && cmd.HasParent() && !(overridden && cmd.Flag(name) != nil) {
 		return s.getFlag(cmd.Parent(), name)
 	} else {
-		entry := s.get(cmd)
+		if flag == nil {
+			flag = cmd.Flag(name)
+		}
 		entry.flagMutex.RLock()
 		defer entry.flagMutex.RUnlock()
 
@@ -113,7 +121,7 @@ func (s _storage) getFlag(cmd *cobra.Command, name string) Action {
 
 func (s _storage) preRun(cmd *cobra.Command, args []string) {
 	if entry := s.get(cmd); entry.prerun != nil {
-		LOG.Printf("executing PreRun for %#v with args %#v", cmd.Name(), args)
+		log.Logger.Debug("executing PreRun", "command", cmd.Name(), "args", args)
 		entry.prerun(cmd, args)
 	}
 }
@@ -192,7 +200,7 @@ func (s _storage) check() []string {
 			defer entry.flagMutex.RUnlock()
 
 			for name := range entry.flag {
-				if flag := cmd.LocalFlags().Lookup(name); flag == nil {
+				if flag := cmd.Flag(name); flag == nil { // local or inherited
 					errors = append(errors, fmt.Sprintf("unknown flag for %s: %s\n", uid.Command(cmd), name))
 				}
 			}
@@ -0,0 +1,9 @@
+// Package capture provides zsh-capture-completion
+package capture
+
+import _ "embed"
+
+// Script captures the completions zsh generates for the command line given as arguments (unmodified upstream script).
+//
+//go:embed capture.zsh
+var Script string
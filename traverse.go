@@ -4,18 +4,19 @@ import (
 	"strings"
 
 	"github.com/carapace-sh/carapace/internal/common"
-	"github.com/carapace-sh/carapace/internal/env"
+	"github.com/carapace-sh/carapace/internal/log"
 	"github.com/carapace-sh/carapace/internal/pflagfork"
+	"github.com/carapace-sh/carapace/pkg/settings"
 	"github.com/carapace-sh/carapace/pkg/style"
 	"github.com/spf13/cobra"
 )
 
 func traverse(cmd *cobra.Command, args []string) (Action, Context) {
-	LOG.Printf("traverse called for %#v with args %#v\n", cmd.Name(), args)
+	log.Logger.Debug("traverse called", "command", cmd.Name(), "args", args)
 	storage.preRun(cmd, args)
 
-	if env.Lenient() {
-		LOG.Printf("allowing unknown flags")
+	if settings.Carapace.Lenient {
+		log.Logger.Debug("allowing unknown flags", "command", cmd.Name())
 		cmd.FParseErrWhitelist.UnknownFlags = true
 	}
 
@@ -32,7 +33,7 @@ loop:
 		switch {
 		// flag argument
 		case inFlag != nil && inFlag.Consumes(arg):
-			LOG.Printf("arg %#v is a flag argument\n", arg)
+			log.Logger.Debug("arg is a flag argument", "arg", arg, "flag", inFlag.Name)
 			inArgs = append(inArgs, arg)
 			inFlag.Args = append(inFlag.Args, arg)
 
@@ -43,31 +44,31 @@ loop:
 
 		// dash
 		case arg == "--":
-			LOG.Printf("arg %#v is dash\n", arg)
+			log.Logger.Debug("arg is dash", "arg", arg)
 			inArgs = append(inArgs, context.Args[i:]...)
 			break loop
 
 		// flag
 		case !cmd.DisableFlagParsing && strings.HasPrefix(arg, "-") && (fs.IsInterspersed() || len(inPositionals) == 0):
-			LOG.Printf("arg %#v is a flag\n", arg)
+			log.Logger.Debug("arg is a flag", "arg", arg)
 			inArgs = append(inArgs, arg)
 			inFlag = fs.LookupArg(arg)
 
 			if inFlag == nil {
-				LOG.Printf("flag %#v is unknown", arg)
+				log.Logger.Debug("flag is unknown", "arg", arg)
 			}
 			continue
 
 		// subcommand
 		case subcommand(cmd, arg) != nil:
-			LOG.Printf("arg %#v is a subcommand\n", arg)
+			log.Logger.Debug("arg is a subcommand", "arg", arg)
 
 			switch {
 			case cmd.DisableFlagParsing:
-				LOG.Printf("flag parsing disabled for %#v\n", cmd.Name())
+				log.Logger.Debug("flag parsing disabled", "command", cmd.Name())
 
 			default:
-				LOG.Printf("parsing flags for %#v with args %#v\n", cmd.Name(), inArgs)
+				log.Logger.Debug("parsing flags", "command", cmd.Name(), "args", inArgs)
 				if err := cmd.ParseFlags(inArgs); err != nil {
 					return ActionMessage(err.Error()), context
 				}
@@ -78,7 +79,7 @@ loop:
 
 		// positional
 		default:
-			LOG.Printf("arg %#v is a positional\n", arg)
+			log.Logger.Debug("arg is a positional", "arg", arg)
 			inArgs = append(inArgs, arg)
 			inPositionals = append(inPositionals, arg)
 		}
@@ -86,19 +87,19 @@ loop:
 
 	toParse := inArgs
 	if inFlag != nil && len(inFlag.Args) == 0 && inFlag.Consumes("") {
-		LOG.Printf("removing arg %#v since it is a flag missing its argument\n", toParse[len(toParse)-1])
+		log.Logger.Debug("removing flag missing its argument", "arg", toParse[len(toParse)-1])
 		toParse = toParse[:len(toParse)-1]
 	} else if (fs.IsInterspersed() || len(inPositionals) == 0) && fs.IsShorthandSeries(context.Value) { // TODO shorthand series isn't correct anymore (can have value attached)
-		LOG.Printf("arg %#v is a shorthand flag series", context.Value) // TODO not aways correct
+		log.Logger.Debug("arg is a shorthand flag series", "arg", context.Value) // TODO not aways correct
 		localInFlag := fs.LookupArg(context.Value)
 
 		if localInFlag != nil && (len(localInFlag.Args) == 0 || localInFlag.Args[0] == "") && (!localInFlag.IsOptarg() || strings.HasSuffix(localInFlag.Prefix, string(localInFlag.OptargDelimiter()))) { // TODO && len(context.Value) > 2 {
 			// TODO check if empty prefix
 			suffix := localInFlag.Prefix[strings.LastIndex(localInFlag.Prefix, localInFlag.Shorthand):]
-			LOG.Printf("removing suffix %#v since it is a flag missing its argument\n", suffix)
+			log.Logger.Debug("removing suffix of flag missing its argument", "suffix", suffix)
 			toParse = append(toParse, strings.TrimSuffix(localInFlag.Prefix, suffix))
 		} else {
-			LOG.Printf("adding shorthand flag %#v", context.Value)
+			log.Logger.Debug("adding shorthand flag", "arg", context.Value)
 			toParse = append(toParse, context.Value)
 		}
 
@@ -107,10 +108,10 @@ loop:
 	// TODO duplicated code
 	switch {
 	case cmd.DisableFlagParsing:
-		LOG.Printf("flag parsing is disabled for %#v\n", cmd.Name())
+		log.Logger.Debug("flag parsing disabled", "command", cmd.Name())
 
 	default:
-		LOG.Printf("parsing flags for %#v with args %#v\n", cmd.Name(), toParse)
+		log.Logger.Debug("parsing flags", "command", cmd.Name(), "args", toParse)
 		if err := cmd.ParseFlags(toParse); err != nil {
 			return ActionMessage(err.Error()), context
 		}
@@ -120,22 +121,22 @@ loop:
 	switch {
 	// dash argument
 	case common.IsDash(cmd):
-		LOG.Printf("completing dash for arg %#v\n", context.Value)
+		log.Logger.Debug("completing dash", "command", cmd.Name(), "value", context.Value)
 		context.Args = cmd.Flags().Args()[cmd.ArgsLenAtDash():]
-		LOG.Printf("context: %#v\n", context.Args)
+		log.Logger.Debug("dash arguments", "args", context.Args)
 
 		return storage.getPositional(cmd, len(context.Args)), context
 
 	// flag argument
 	case inFlag != nil && inFlag.Consumes(context.Value):
-		LOG.Printf("completing flag argument of %#v for arg %#v\n", inFlag.Name, context.Value)
+		log.Logger.Debug("completing flag argument", "command", cmd.Name(), "flag", inFlag.Name, "value", context.Value)
 		context.Parts = inFlag.Args
 		return storage.getFlag(cmd, inFlag.Name), context
 
 	// flag
 	case !cmd.DisableFlagParsing && strings.HasPrefix(context.Value, "-") && (fs.IsInterspersed() || len(inPositionals) == 0):
 		if f := fs.LookupArg(context.Value); f != nil && len(f.Args) > 0 {
-			LOG.Printf("completing optional flag argument for arg %#v with prefix %#v\n", context.Value, f.Prefix)
+			log.Logger.Debug("completing optional flag argument", "command", cmd.Name(), "flag", f.Name, "value", context.Value, "prefix", f.Prefix)
 
 			switch f.Value.Type() {
 			case "bool":
@@ -144,15 +145,15 @@ loop:
 				return storage.getFlag(cmd, f.Name).Prefix(f.Prefix), context
 			}
 		} else if f != nil && fs.IsPosix() && !strings.HasPrefix(context.Value, "--") && !f.IsOptarg() && f.Prefix == context.Value {
-			LOG.Printf("completing attached flag argument for arg %#v with prefix %#v\n", context.Value, f.Prefix)
+			log.Logger.Debug("completing attached flag argument", "command", cmd.Name(), "flag", f.Name, "value", context.Value, "prefix", f.Prefix)
 			return storage.getFlag(cmd, f.Name).Prefix(f.Prefix), context
 		}
-		LOG.Printf("completing flags for arg %#v\n", context.Value)
+		log.Logger.Debug("completing flags", "command", cmd.Name(), "value", context.Value)
 		return actionFlags(cmd), context
 
 	// positional or subcommand
 	default:
-		LOG.Printf("completing positionals and subcommands for arg %#v\n", context.Value)
+		log.Logger.Debug("completing positionals and subcommands", "command", cmd.Name(), "value", context.Value)
 		batch := Batch(storage.getPositional(cmd, len(context.Args)))
 		if cmd.HasAvailableSubCommands() && len(context.Args) == 0 {
 			batch = append(batch, ActionCommands(cmd))